@@ -4,12 +4,24 @@ import (
 	"booking-engine/config"
 	"booking-engine/config/middleware"
 	"booking-engine/internal/handler"
+	"booking-engine/internal/model"
+	"booking-engine/internal/payment"
 	"booking-engine/internal/repository"
 	"booking-engine/internal/usecase"
+	"booking-engine/internal/worker"
+	"booking-engine/internal/workflow"
+	"booking-engine/internal/workflow/resources"
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
+	// The zone database is embedded so FLIGHT_TIMEZONE resolves on images that do not ship one
+	_ "time/tzdata"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -18,6 +30,7 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/joho/godotenv"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
 )
 
 func main() {
@@ -38,19 +51,175 @@ func main() {
 		DB: db,
 	})
 
+	// Initialize the passenger repository
+	passengerRepo := repository.NewPassengerRepository(repository.PassengerRepository{
+		DB: db,
+	})
+
+	// Search dates and times are local to the departure airports, FLIGHT_TIMEZONE defaults to Asia/Jakarta
+	timezone := os.Getenv("FLIGHT_TIMEZONE")
+	if timezone == "" {
+		timezone = usecase.DefaultTimezone
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		baseDep.Logger.Error("invalid FLIGHT_TIMEZONE", zap.Error(err))
+		os.Exit(1)
+	}
+
+	cacher := config.NewCacher(baseDep.Logger)
+	seatHoldTTL, _ := time.ParseDuration(os.Getenv("SEAT_HOLD_TTL"))
+	quoteTTL, _ := time.ParseDuration(os.Getenv("QUOTE_TTL"))
+	paymentWindow, _ := time.ParseDuration(os.Getenv("RESERVATION_PAYMENT_WINDOW"))
+	expiryInterval, _ := time.ParseDuration(os.Getenv("RESERVATION_EXPIRY_INTERVAL"))
+	outboxInterval, _ := time.ParseDuration(os.Getenv("WORKFLOW_OUTBOX_INTERVAL"))
+	fareRules := usecase.DefaultFareRules()
+	if raw := os.Getenv("FARE_RULES"); raw != "" {
+		if fareRules, err = usecase.ParseFareRules(raw); err != nil {
+			baseDep.Logger.Error("invalid FARE_RULES", zap.Error(err))
+			os.Exit(1)
+		}
+	}
+	quoteKey := os.Getenv("QUOTE_SIGNING_KEY")
+	if quoteKey == "" {
+		baseDep.Logger.Error("no QUOTE_SIGNING_KEY provided")
+		os.Exit(1)
+	}
+	settlementDelay, _ := time.ParseDuration(os.Getenv("MOCK_PAYMENT_SETTLEMENT_DELAY"))
+	paymentProvider := payment.NewMockProvider(os.Getenv("MOCK_PAYMENT_SCENARIO"), settlementDelay)
+	callbackSecret := os.Getenv("PAYMENT_CALLBACK_SECRET")
+	if callbackSecret == "" {
+		baseDep.Logger.Error("no PAYMENT_CALLBACK_SECRET provided")
+		os.Exit(1)
+	}
+
+	// Initialize the workflow engine, shared by every request and closed on shutdown
+	var operateClient *workflow.OperateClient
+	if operateURL := os.Getenv("OPERATE_URL"); operateURL != "" {
+		operateClient = workflow.NewOperateClient(operateURL, os.Getenv("OPERATE_TOKEN"))
+	}
+	var workflowEngine workflow.WorkflowEngine
+	if os.Getenv("WORKFLOW_ENGINE") == "memory" {
+		workflowEngine = workflow.NewMemoryEngine()
+	} else if workflowEngine, err = workflow.NewZeebeEngine(os.Getenv("ZEEBE_ADDRESS"), baseDep.Logger, operateClient); err != nil {
+		baseDep.Logger.Error("failed to create zeebe client", zap.Error(err))
+		os.Exit(1)
+	}
+	defer workflowEngine.Close()
+
+	// WORKFLOW_PROCESS_VERSION is "latest" (the default), "deployed" for the version deployed on startup, or a number
+	process := model.ProcessDefinition{ProcessID: os.Getenv("WORKFLOW_PROCESS_ID")}
+	pinDeployed := false
+	switch raw := os.Getenv("WORKFLOW_PROCESS_VERSION"); raw {
+	case "", "latest":
+	case "deployed":
+		// The deployed version is only known when the resources are deployed on startup
+		if os.Getenv("WORKFLOW_DEPLOY") == "false" {
+			baseDep.Logger.Error("WORKFLOW_PROCESS_VERSION=deployed needs WORKFLOW_DEPLOY enabled")
+			os.Exit(1)
+		}
+		pinDeployed = true
+	default:
+		version, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil || version <= 0 {
+			baseDep.Logger.Error("invalid WORKFLOW_PROCESS_VERSION", zap.String("value", raw))
+			os.Exit(1)
+		}
+		process.Version = int32(version)
+	}
+
+	// Deploy the embedded BPMN resources. Bookings are queued in the outbox while the engine is unreachable,
+	// so a failed deployment only stops startup when new instances are pinned to the deployed version.
+	workflowUsecase := usecase.NewWorkflowUsecaseService(&usecase.WorkflowUsecase{
+		FlightRepo: flightRepo,
+		Workflow:   workflowEngine,
+		Logger:     baseDep.Logger,
+	})
+	if os.Getenv("WORKFLOW_DEPLOY") != "false" {
+		deployments, err := workflowUsecase.DeployWorkflows(resources.FS)
+		if err == nil && pinDeployed {
+			process, err = usecase.PinDeployedVersion(process, deployments)
+		}
+		if err != nil {
+			baseDep.Logger.Error("failed to deploy workflows", zap.Error(err))
+			if pinDeployed {
+				os.Exit(1)
+			}
+		}
+	}
+
 	// Initialize the flight usecase
 	flightUscase := usecase.NewFlightUsecaseService(&usecase.FlightUsecase{
-		FlightRepo: flightRepo,
+		FlightRepo:    flightRepo,
+		PassengerRepo: passengerRepo,
+		Cacher:        cacher,
+		SeatHoldTTL:   seatHoldTTL,
+		Location:      location,
+		Pricing:       usecase.NewPricingEngine(usecase.DefaultPricingRules()),
+		Quotes:        usecase.NewQuoteSigner([]byte(quoteKey), quoteTTL),
+		FareRules:     fareRules,
+		Workflow:      workflowEngine,
+		PaymentWindow: paymentWindow,
+		Logger:        baseDep.Logger,
+		Payments:      paymentProvider,
+		Process:       process,
+
+		PaymentCallbackSecret: []byte(callbackSecret),
 	})
 
+	// Initialize the passenger usecase
+	passengerUsecase := usecase.NewPassengerUsecaseService(&usecase.PassengerUsecase{
+		PassengerRepo: passengerRepo,
+	})
+
+	// Initialize the reservation expiry worker
+	expiryWorker := worker.NewExpiryWorker(worker.ExpiryWorker{
+		Usecase:  flightUscase,
+		Interval: expiryInterval,
+		Logger:   baseDep.Logger,
+	}, prometheus.DefaultRegisterer)
+
+	// Initialize the workflow outbox dispatcher
+	outboxDispatcher := worker.NewOutboxDispatcher(worker.OutboxDispatcher{
+		Usecase:  flightUscase,
+		Interval: outboxInterval,
+		Logger:   baseDep.Logger,
+	}, prometheus.DefaultRegisterer)
+
+	// Work on the service tasks of the booking process in this process when enabled
+	if os.Getenv("WORKFLOW_JOB_WORKERS") == "true" {
+		jobWorkers := worker.NewJobWorkers(worker.JobWorkers{
+			Usecase: flightUscase,
+			Engine:  workflowEngine,
+			Logger:  baseDep.Logger,
+		}, prometheus.DefaultRegisterer)
+		if err := jobWorkers.Register(); err != nil {
+			baseDep.Logger.Error("failed to register job workers", zap.Error(err))
+			os.Exit(1)
+		}
+	}
+
 	// Initialize the flight handler
 	flightHandler := handler.NewHandler(handler.Handler{
-		Usecase: flightUscase,
+		Usecase:          flightUscase,
+		PassengerUsecase: passengerUsecase,
 	})
 
-	app := fiber.New(fiber.Config{
+	// Client IPs are only taken from the proxy header when the request comes through one of TRUSTED_PROXIES,
+	// the header defaults to X-Real-IP which the proxy overwrites instead of appending to like X-Forwarded-For
+	fiberConfig := fiber.Config{
 		BodyLimit: 30 * 1024 * 1024,
-	})
+	}
+	if raw := os.Getenv("TRUSTED_PROXIES"); raw != "" {
+		fiberConfig.EnableTrustedProxyCheck = true
+		fiberConfig.EnableIPValidation = true
+		fiberConfig.TrustedProxies = strings.Split(strings.ReplaceAll(raw, " ", ""), ",")
+		fiberConfig.ProxyHeader = os.Getenv("PROXY_HEADER")
+		if fiberConfig.ProxyHeader == "" {
+			fiberConfig.ProxyHeader = "X-Real-IP"
+		}
+	}
+	app := fiber.New(fiberConfig)
 
 	app.Use(fiberProm.Middleware)
 	app.Use(recover.New())
@@ -70,9 +239,45 @@ func main() {
 	//=== healthz route
 	app.Get("/healthz", Healthz)
 	//=== reservation route
+	app.Get("/flights", flightHandler.SearchFlights)
+	app.Post("/flights", flightHandler.UpsertFlight)
 	app.Get("/flights/:id", flightHandler.GetFlightByID)
+	app.Get("/flights/:id/fare", flightHandler.GetFare)
+	app.Get("/flights/:id/seats", flightHandler.GetSeatMap)
+	app.Post("/flights/:id/seats/:seat/hold", flightHandler.HoldSeat)
+	app.Delete("/flights/:id/seats/:seat/hold", flightHandler.ReleaseSeatHold)
+	app.Post("/seat-layouts", flightHandler.UpsertSeatLayout)
+	app.Post("/quotes", flightHandler.CreateQuote)
 	app.Post("/bookings", flightHandler.BookFlight)
 	app.Get("/bookings", flightHandler.GetAllReservations)
+	app.Get("/bookings/lookup", flightHandler.LookupBooking)
+	app.Get("/bookings/:id", flightHandler.GetBookingByID)
+	app.Patch("/bookings/:id/status", flightHandler.TransitionReservation)
+	app.Post("/bookings/:id/cancel", flightHandler.CancelReservation)
+	app.Post("/bookings/:id/modify", flightHandler.ModifyReservation)
+	app.Get("/bookings/:id/changes", flightHandler.GetReservationChanges)
+	app.Get("/bookings/:id/workflow", flightHandler.GetReservationWorkflow)
+
+	//=== payment route
+	app.Post("/bookings/:id/payment", flightHandler.CreatePayment)
+	app.Get("/bookings/:id/payment", flightHandler.GetPayment)
+	app.Post("/payments/callback", flightHandler.PaymentCallback)
+
+	//=== passenger route
+	app.Post("/passengers", flightHandler.CreatePassenger)
+	app.Get("/passengers/:id", flightHandler.GetPassengerByID)
+	app.Put("/passengers/:id", flightHandler.UpdatePassenger)
+	app.Delete("/passengers/:id", flightHandler.DeletePassenger)
+
+	//=== background workers, stopped on SIGINT/SIGTERM together with the server ===//
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go expiryWorker.Run(ctx)
+	go outboxDispatcher.Run(ctx)
+	go func() {
+		<-ctx.Done()
+		_ = app.Shutdown()
+	}()
 
 	//=== listen port ===//
 	if err := app.Listen(fmt.Sprintf(":%s", "3002")); err != nil {
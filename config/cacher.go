@@ -2,6 +2,7 @@ package config
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"time"
@@ -15,11 +16,39 @@ type Cache struct {
 	defaultExp time.Duration
 }
 
+// ErrCacheMiss is returned by Get when the key does not exist
+var ErrCacheMiss = errors.New("cache miss")
+
+// The compare scripts check the current value and act on the key in one step, so a value can only be renewed
+// or removed by whoever wrote it even when the key expires or changes hands concurrently
+var (
+	expireIfEqualScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+	delIfEqualScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+	// incrScript starts the expiry together with the counter, a counter can never be left without one
+	incrScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 and tonumber(ARGV[1]) > 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return count`)
+)
+
 type Cacher interface {
 	Set(ctx context.Context, key string, value interface{}, duration time.Duration) error
-	SetNX(ctx context.Context, key string, value interface{}, duration time.Duration) error
+	SetNX(ctx context.Context, key string, value interface{}, duration time.Duration) (bool, error)
+	ExpireIfEqual(ctx context.Context, key string, value string, duration time.Duration) (bool, error)
 	Get(ctx context.Context, key string) (string, error)
+	Incr(ctx context.Context, key string, duration time.Duration) (int64, error)
 	Del(ctx context.Context, key string) error
+	DelIfEqual(ctx context.Context, key string, value string) (bool, error)
 }
 
 func NewCacher(logger Logger) Cacher {
@@ -51,29 +80,56 @@ func (c *Cache) Set(ctx context.Context, key string, value interface{}, duration
 	return nil
 }
 
-func (c *Cache) SetNX(ctx context.Context, key string, value interface{}, duration time.Duration) error {
+// SetNX sets the key only if it does not exist yet and reports whether it was set
+func (c *Cache) SetNX(ctx context.Context, key string, value interface{}, duration time.Duration) (bool, error) {
 	fullKey := fmt.Sprintf("%s:%s", c.service, key)
 	if duration == 0*time.Second {
 		duration = 0
 	}
 
-	if err := c.db.SetNX(ctx, fullKey, value, duration).Err(); err != nil {
-		return err
+	ok, err := c.db.SetNX(ctx, fullKey, value, duration).Result()
+	if err != nil {
+		return false, err
 	}
 
-	return nil
+	return ok, nil
+}
+
+// ExpireIfEqual renews the expiry of the key only while it still holds value and reports whether it did
+func (c *Cache) ExpireIfEqual(ctx context.Context, key string, value string, duration time.Duration) (bool, error) {
+	fullKey := fmt.Sprintf("%s:%s", c.service, key)
+	renewed, err := expireIfEqualScript.Run(ctx, c.db, []string{fullKey}, value, duration.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+
+	return renewed == 1, nil
 }
 
 func (c *Cache) Get(ctx context.Context, key string) (string, error) {
 	fullKey := fmt.Sprintf("%s:%s", c.service, key)
 	value, err := c.db.Get(ctx, fullKey).Result()
 	if err != nil {
+		if err == redis.Nil {
+			return "", ErrCacheMiss
+		}
 		return "", err
 	}
 
 	return value, nil
 }
 
+// Incr increments the counter stored at key, starting its expiry when the counter is created
+func (c *Cache) Incr(ctx context.Context, key string, duration time.Duration) (int64, error) {
+	fullKey := fmt.Sprintf("%s:%s", c.service, key)
+	count, err := incrScript.Run(ctx, c.db, []string{fullKey}, duration.Milliseconds()).Int64()
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
 func (c *Cache) Del(ctx context.Context, key string) error {
 	fullKey := fmt.Sprintf("%s:%s", c.service, key)
 	_, err := c.db.Del(ctx, fullKey).Result()
@@ -83,3 +139,14 @@ func (c *Cache) Del(ctx context.Context, key string) error {
 
 	return nil
 }
+
+// DelIfEqual deletes the key only while it still holds value and reports whether it did
+func (c *Cache) DelIfEqual(ctx context.Context, key string, value string) (bool, error) {
+	fullKey := fmt.Sprintf("%s:%s", c.service, key)
+	deleted, err := delIfEqualScript.Run(ctx, c.db, []string{fullKey}, value).Int()
+	if err != nil {
+		return false, err
+	}
+
+	return deleted == 1, nil
+}
@@ -12,8 +12,10 @@ import (
 )
 
 func NewDbPool(logger Logger) (*sql.DB, error) {
+	// clientFoundRows makes updates report matched rows rather than changed ones, so rewriting a row with the
+	// values it already holds is not mistaken for a missing row
 	dsn := fmt.Sprintf(
-		"%s:%s@tcp(%s:%s)/%s?parseTime=true&charset=utf8mb4&collation=utf8mb4_unicode_ci",
+		"%s:%s@tcp(%s:%s)/%s?parseTime=true&clientFoundRows=true&charset=utf8mb4&collation=utf8mb4_unicode_ci",
 		os.Getenv("DATABASE_USER"),
 		os.Getenv("DATABASE_PASSWORD"),
 		os.Getenv("DATABASE_HOST"),
@@ -3,18 +3,42 @@ package handler
 import (
 	"booking-engine/internal/model"
 	"booking-engine/internal/usecase"
+	"errors"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 // Handler handles HTTP requests for flights and bookings
 type Handler struct {
-	Usecase usecase.FlightExecutor
+	Usecase          usecase.FlightExecutor
+	PassengerUsecase usecase.PassengerExecutor
 }
 type FlightaHandler interface {
 	GetFlightByID(c *fiber.Ctx) error
+	SearchFlights(c *fiber.Ctx) error
+	UpsertFlight(c *fiber.Ctx) error
+	GetSeatMap(c *fiber.Ctx) error
+	UpsertSeatLayout(c *fiber.Ctx) error
+	HoldSeat(c *fiber.Ctx) error
+	ReleaseSeatHold(c *fiber.Ctx) error
+	GetFare(c *fiber.Ctx) error
+	CreateQuote(c *fiber.Ctx) error
 	BookFlight(c *fiber.Ctx) error
 	GetAllReservations(c *fiber.Ctx) error
+	LookupBooking(c *fiber.Ctx) error
+	GetBookingByID(c *fiber.Ctx) error
+	TransitionReservation(c *fiber.Ctx) error
+	CancelReservation(c *fiber.Ctx) error
+	ModifyReservation(c *fiber.Ctx) error
+	GetReservationChanges(c *fiber.Ctx) error
+	GetReservationWorkflow(c *fiber.Ctx) error
+	PaymentCallback(c *fiber.Ctx) error
+	CreatePayment(c *fiber.Ctx) error
+	GetPayment(c *fiber.Ctx) error
+	CreatePassenger(c *fiber.Ctx) error
+	GetPassengerByID(c *fiber.Ctx) error
+	UpdatePassenger(c *fiber.Ctx) error
+	DeletePassenger(c *fiber.Ctx) error
 }
 
 // NewHandler creates a new instance of the flight handler
@@ -36,6 +60,92 @@ func (h *Handler) GetFlightByID(c *fiber.Ctx) error {
 	return c.JSON(flight)
 }
 
+// SearchFlights handles the GET /flights endpoint
+func (h *Handler) SearchFlights(c *fiber.Ctx) error {
+	var request model.FlightSearchRequest
+	if err := c.QueryParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid search parameters")
+	}
+
+	result, err := h.Usecase.SearchFlights(request)
+	if err != nil {
+		if err == model.ErrInvalidSearch {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid search parameters")
+		}
+		return c.Status(fiber.StatusInternalServerError).SendString("Internal Server Error")
+	}
+
+	return c.JSON(result)
+}
+
+// UpsertFlight handles the POST /flights endpoint
+func (h *Handler) UpsertFlight(c *fiber.Ctx) error {
+	var request model.Flight
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request format")
+	}
+
+	flight, err := h.Usecase.UpsertFlight(request)
+	if err != nil {
+		if err == model.ErrInvalidFlight {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid flight")
+		}
+		return c.Status(fiber.StatusInternalServerError).SendString("Internal Server Error")
+	}
+
+	return c.JSON(flight)
+}
+
+// GetFare handles the GET /flights/:id/fare endpoint
+func (h *Handler) GetFare(c *fiber.Ctx) error {
+	var request model.FareRequest
+	if err := c.QueryParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request format")
+	}
+
+	fare, err := h.Usecase.GetFare(c.Params("id"), request)
+	if err != nil {
+		switch err {
+		case model.ErrFlightNotFound:
+			return c.Status(fiber.StatusNotFound).SendString("Flight not found")
+		case model.ErrInvalidFareClass:
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid fare class")
+		case model.ErrInvalidPassengerType:
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid passenger type")
+		}
+		return c.Status(fiber.StatusInternalServerError).SendString("Internal Server Error")
+	}
+
+	return c.JSON(fare)
+}
+
+// CreateQuote handles the POST /quotes endpoint
+func (h *Handler) CreateQuote(c *fiber.Ctx) error {
+	var request model.QuoteRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request format")
+	}
+
+	quote, err := h.Usecase.CreateQuote(request)
+	if err != nil {
+		switch err {
+		case model.ErrFlightNotFound:
+			return c.Status(fiber.StatusNotFound).SendString("Flight not found")
+		case model.ErrInvalidQuote:
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid quote request")
+		case model.ErrInvalidFareClass:
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid fare class")
+		case model.ErrInvalidPassengerType:
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid passenger type")
+		case model.ErrFlightSoldOut:
+			return c.Status(fiber.StatusConflict).SendString("Flight is sold out")
+		}
+		return c.Status(fiber.StatusInternalServerError).SendString("Internal Server Error")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(quote)
+}
+
 // BookFlight handles the POST /bookings endpoint
 func (h *Handler) BookFlight(c *fiber.Ctx) error {
 	var request model.BookingRequest
@@ -45,6 +155,40 @@ func (h *Handler) BookFlight(c *fiber.Ctx) error {
 
 	booking, err := h.Usecase.BookFlight(request)
 	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrInvalidBooking):
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid booking request")
+		case errors.Is(err, model.ErrFlightNotFound):
+			return c.Status(fiber.StatusNotFound).SendString("Flight not found")
+		case errors.Is(err, model.ErrPassengerNotFound):
+			return c.Status(fiber.StatusNotFound).SendString("Passenger not found")
+		case errors.Is(err, model.ErrSeatLayoutNotFound):
+			return c.Status(fiber.StatusNotFound).SendString("Seat layout not found")
+		case errors.Is(err, model.ErrSeatNotFound):
+			return c.Status(fiber.StatusBadRequest).SendString("Seat does not exist")
+		case errors.Is(err, model.ErrInvalidFareClass):
+			return c.Status(fiber.StatusBadRequest).SendString("Fare class does not match the seat")
+		case errors.Is(err, model.ErrInvalidPassengerType):
+			return c.Status(fiber.StatusBadRequest).SendString("Passenger type does not match the age at departure")
+		case errors.Is(err, model.ErrPriceMismatch):
+			return c.Status(fiber.StatusConflict).SendString("Price does not match the current fare")
+		case errors.Is(err, model.ErrQuoteInvalid):
+			return c.Status(fiber.StatusBadRequest).SendString("Quote token is invalid")
+		case errors.Is(err, model.ErrQuoteExpired):
+			return c.Status(fiber.StatusGone).SendString("Quote token has expired")
+		case errors.Is(err, model.ErrQuoteMismatch):
+			return c.Status(fiber.StatusConflict).SendString("Quote does not match the booking")
+		case errors.Is(err, model.ErrSeatUnavailable), errors.Is(err, model.ErrSeatTaken):
+			return c.Status(fiber.StatusConflict).SendString("Seat is not available")
+		case errors.Is(err, model.ErrSeatHeld):
+			return c.Status(fiber.StatusConflict).SendString("Seat is held by another customer")
+		case errors.Is(err, model.ErrFlightSoldOut):
+			return c.Status(fiber.StatusConflict).SendString("Flight is sold out")
+		case errors.Is(err, model.ErrValidationFailed):
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid booking request")
+		case errors.Is(err, model.ErrPersistenceFailed):
+			return c.Status(fiber.StatusServiceUnavailable).SendString("Booking could not be saved, try again")
+		}
 		return c.Status(fiber.StatusInternalServerError).SendString("Internal Server Error")
 	}
 	return c.JSON(booking)
@@ -52,11 +196,217 @@ func (h *Handler) BookFlight(c *fiber.Ctx) error {
 
 // GetBookings handles the GET /bookings endpoint
 func (h *Handler) GetAllReservations(c *fiber.Ctx) error {
-	var reservations []model.Reservation
-	reservations, err := h.Usecase.GetAllReservations()
+	var request model.ReservationListRequest
+	if err := c.QueryParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request format")
+	}
+
+	reservations, err := h.Usecase.GetAllReservations(request)
 	if err != nil {
+		if err == model.ErrInvalidListRequest {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid list parameters")
+		}
 		return c.Status(fiber.StatusInternalServerError).SendString("Internal Server Error")
 	}
 
 	return c.JSON(reservations)
 }
+
+// LookupBooking handles the GET /bookings/lookup endpoint
+func (h *Handler) LookupBooking(c *fiber.Ctx) error {
+	var request model.BookingLookupRequest
+	if err := c.QueryParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request format")
+	}
+
+	booking, err := h.Usecase.LookupBooking(request, c.IP())
+	if err != nil {
+		switch err {
+		case model.ErrInvalidLookup:
+			return c.Status(fiber.StatusBadRequest).SendString("Locator and last name are required")
+		case model.ErrReservationNotFound:
+			return c.Status(fiber.StatusNotFound).SendString("Booking not found")
+		case model.ErrTooManyAttempts:
+			return c.Status(fiber.StatusTooManyRequests).SendString("Too many attempts, try again later")
+		}
+		return c.Status(fiber.StatusInternalServerError).SendString("Internal Server Error")
+	}
+
+	return c.JSON(booking)
+}
+
+// GetBookingByID handles the GET /bookings/:id endpoint
+func (h *Handler) GetBookingByID(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid booking ID")
+	}
+
+	booking, err := h.Usecase.GetBookingByID(id)
+	if err != nil {
+		if err == model.ErrReservationNotFound {
+			return c.Status(fiber.StatusNotFound).SendString("Booking not found")
+		}
+		return c.Status(fiber.StatusInternalServerError).SendString("Internal Server Error")
+	}
+
+	return c.JSON(booking)
+}
+
+// TransitionReservation handles the PATCH /bookings/:id/status endpoint
+func (h *Handler) TransitionReservation(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid booking ID")
+	}
+
+	var request model.StatusChangeRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request format")
+	}
+
+	reservation, err := h.Usecase.TransitionReservation(id, request.Status)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrUnknownStatus):
+			return c.Status(fiber.StatusBadRequest).SendString("Unknown status")
+		case errors.Is(err, model.ErrStatusNotSettable):
+			return c.Status(fiber.StatusUnprocessableEntity).SendString("Use the payment, cancel or expiry flow for this status")
+		case errors.Is(err, model.ErrReservationNotFound):
+			return c.Status(fiber.StatusNotFound).SendString("Booking not found")
+		case errors.Is(err, model.ErrInvalidTransition):
+			return c.Status(fiber.StatusConflict).SendString(err.Error())
+		case errors.Is(err, model.ErrStatusConflict):
+			return c.Status(fiber.StatusConflict).SendString("Booking was changed concurrently, try again")
+		}
+		return c.Status(fiber.StatusInternalServerError).SendString("Internal Server Error")
+	}
+
+	return c.JSON(reservation)
+}
+
+// CancelReservation handles the POST /bookings/:id/cancel endpoint
+func (h *Handler) CancelReservation(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid booking ID")
+	}
+
+	reservation, err := h.Usecase.CancelReservation(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrReservationNotFound):
+			return c.Status(fiber.StatusNotFound).SendString("Booking not found")
+		case errors.Is(err, model.ErrInvalidTransition):
+			return c.Status(fiber.StatusConflict).SendString(err.Error())
+		case errors.Is(err, model.ErrFlightDeparted):
+			return c.Status(fiber.StatusConflict).SendString("Flight has already departed")
+		case errors.Is(err, model.ErrStatusConflict):
+			return c.Status(fiber.StatusConflict).SendString("Booking was changed concurrently, try again")
+		}
+		return c.Status(fiber.StatusInternalServerError).SendString("Internal Server Error")
+	}
+
+	return c.JSON(reservation)
+}
+
+// ModifyReservation handles the POST /bookings/:id/modify endpoint
+func (h *Handler) ModifyReservation(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid booking ID")
+	}
+
+	var request model.ModifyBookingRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request format")
+	}
+
+	change, err := h.Usecase.ModifyReservation(id, request)
+	if err != nil {
+		switch err {
+		case model.ErrInvalidModification:
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid booking modification")
+		case model.ErrSeatNotFound:
+			return c.Status(fiber.StatusBadRequest).SendString("Seat does not exist")
+		case model.ErrReservationNotFound:
+			return c.Status(fiber.StatusNotFound).SendString("Booking not found")
+		case model.ErrFlightNotFound:
+			return c.Status(fiber.StatusNotFound).SendString("Flight not found")
+		case model.ErrSeatLayoutNotFound:
+			return c.Status(fiber.StatusNotFound).SendString("Seat layout not found")
+		case model.ErrBookingNotModifiable:
+			return c.Status(fiber.StatusConflict).SendString("Booking can no longer be modified")
+		case model.ErrChangeNotPaid:
+			return c.Status(fiber.StatusPaymentRequired).SendString("Change costs extra, pay the amount due")
+		case model.ErrInvalidPaymentMethod:
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid payment method")
+		case model.ErrChargeNotFound:
+			return c.Status(fiber.StatusNotFound).SendString("Payment not found")
+		case model.ErrChargeAlreadyUsed:
+			return c.Status(fiber.StatusConflict).SendString("Payment was already used")
+		case model.ErrPaymentAmountMismatch:
+			return c.Status(fiber.StatusConflict).SendString("Paid amount does not match the change, the payment is refunded")
+		case model.ErrFlightDeparted:
+			return c.Status(fiber.StatusConflict).SendString("Flight has already departed")
+		case model.ErrSeatUnavailable, model.ErrSeatTaken:
+			return c.Status(fiber.StatusConflict).SendString("Seat is not available")
+		case model.ErrSeatHeld:
+			return c.Status(fiber.StatusConflict).SendString("Seat is held by another customer")
+		case model.ErrFlightSoldOut:
+			return c.Status(fiber.StatusConflict).SendString("Flight is sold out")
+		case model.ErrStatusConflict:
+			return c.Status(fiber.StatusConflict).SendString("Booking was changed concurrently, try again")
+		}
+		return c.Status(fiber.StatusInternalServerError).SendString("Internal Server Error")
+	}
+	if change.ChangeID == 0 {
+		// The change waits for its charge to be paid
+		return c.Status(fiber.StatusAccepted).JSON(change)
+	}
+
+	return c.JSON(change)
+}
+
+// GetReservationChanges handles the GET /bookings/:id/changes endpoint
+func (h *Handler) GetReservationChanges(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid booking ID")
+	}
+
+	changes, err := h.Usecase.GetReservationChanges(id)
+	if err != nil {
+		if err == model.ErrReservationNotFound {
+			return c.Status(fiber.StatusNotFound).SendString("Booking not found")
+		}
+		return c.Status(fiber.StatusInternalServerError).SendString("Internal Server Error")
+	}
+
+	return c.JSON(changes)
+}
+
+// GetReservationWorkflow handles the GET /bookings/:id/workflow endpoint
+func (h *Handler) GetReservationWorkflow(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid booking ID")
+	}
+
+	status, err := h.Usecase.GetReservationWorkflow(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrReservationNotFound):
+			return c.Status(fiber.StatusNotFound).SendString("Booking not found")
+		case errors.Is(err, model.ErrWorkflowInstanceNotFound):
+			return c.Status(fiber.StatusNotFound).SendString("Process instance not found")
+		case errors.Is(err, model.ErrWorkflowNoInspection):
+			return c.Status(fiber.StatusNotImplemented).SendString("Workflow status is not available, Operate is not configured")
+		case errors.Is(err, model.ErrWorkflowUnavailable):
+			return c.Status(fiber.StatusServiceUnavailable).SendString("Workflow engine is unavailable, try again")
+		}
+		return c.Status(fiber.StatusInternalServerError).SendString("Internal Server Error")
+	}
+
+	return c.JSON(status)
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"booking-engine/internal/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CreatePassenger handles the POST /passengers endpoint
+func (h *Handler) CreatePassenger(c *fiber.Ctx) error {
+	var request model.Passenger
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request format")
+	}
+
+	passenger, err := h.PassengerUsecase.CreatePassenger(request)
+	if err != nil {
+		return passengerError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(passenger)
+}
+
+// GetPassengerByID handles the GET /passengers/:id endpoint
+func (h *Handler) GetPassengerByID(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid passenger ID")
+	}
+
+	passenger, err := h.PassengerUsecase.GetPassengerByID(id)
+	if err != nil {
+		return passengerError(c, err)
+	}
+
+	return c.JSON(passenger)
+}
+
+// UpdatePassenger handles the PUT /passengers/:id endpoint
+func (h *Handler) UpdatePassenger(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid passenger ID")
+	}
+
+	var request model.Passenger
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request format")
+	}
+	request.PassengerID = id
+
+	passenger, err := h.PassengerUsecase.UpdatePassenger(request)
+	if err != nil {
+		return passengerError(c, err)
+	}
+
+	return c.JSON(passenger)
+}
+
+// DeletePassenger handles the DELETE /passengers/:id endpoint
+func (h *Handler) DeletePassenger(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid passenger ID")
+	}
+
+	if err := h.PassengerUsecase.DeletePassenger(id); err != nil {
+		return passengerError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func passengerError(c *fiber.Ctx, err error) error {
+	switch err {
+	case model.ErrInvalidPassenger:
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid passenger")
+	case model.ErrPassengerNotFound:
+		return c.Status(fiber.StatusNotFound).SendString("Passenger not found")
+	case model.ErrPassengerInUse:
+		return c.Status(fiber.StatusConflict).SendString("Passenger has reservations")
+	}
+	return c.Status(fiber.StatusInternalServerError).SendString("Internal Server Error")
+}
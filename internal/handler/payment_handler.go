@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"booking-engine/internal/model"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const callbackSignatureHeader = "X-Callback-Signature"
+
+// PaymentCallback handles the POST /payments/callback endpoint called by the payment gateway
+func (h *Handler) PaymentCallback(c *fiber.Ctx) error {
+	err := h.Usecase.HandlePaymentCallback(c.Body(), c.Get(callbackSignatureHeader))
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrInvalidSignature):
+			return c.Status(fiber.StatusUnauthorized).SendString("Invalid signature")
+		case errors.Is(err, model.ErrInvalidCallback):
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid payment callback")
+		case errors.Is(err, model.ErrPaymentAmountMismatch):
+			return c.Status(fiber.StatusBadRequest).SendString("Paid amount does not match the booking")
+		case errors.Is(err, model.ErrReservationNotFound):
+			return c.Status(fiber.StatusNotFound).SendString("Booking not found")
+		case errors.Is(err, model.ErrInvalidTransition):
+			return c.Status(fiber.StatusConflict).SendString(err.Error())
+		case errors.Is(err, model.ErrStatusConflict):
+			return c.Status(fiber.StatusConflict).SendString("Booking was changed concurrently, try again")
+		case errors.Is(err, model.ErrWorkflowUnavailable), errors.Is(err, model.ErrPersistenceFailed):
+			return c.Status(fiber.StatusServiceUnavailable).SendString("Payment could not be processed, try again")
+		}
+		return c.Status(fiber.StatusInternalServerError).SendString("Internal Server Error")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// CreatePayment handles the POST /bookings/:id/payment endpoint
+func (h *Handler) CreatePayment(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid booking ID")
+	}
+
+	var request model.PaymentRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request format")
+	}
+
+	charge, err := h.Usecase.CreatePayment(id, request)
+	if err != nil {
+		return paymentError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(charge)
+}
+
+// GetPayment handles the GET /bookings/:id/payment endpoint
+func (h *Handler) GetPayment(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid booking ID")
+	}
+
+	charge, err := h.Usecase.GetPayment(id)
+	if err != nil {
+		return paymentError(c, err)
+	}
+
+	return c.JSON(charge)
+}
+
+func paymentError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, model.ErrInvalidPaymentMethod):
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid payment method")
+	case errors.Is(err, model.ErrReservationNotFound):
+		return c.Status(fiber.StatusNotFound).SendString("Booking not found")
+	case errors.Is(err, model.ErrChargeNotFound):
+		return c.Status(fiber.StatusNotFound).SendString("Payment not found")
+	case errors.Is(err, model.ErrNotPayable):
+		return c.Status(fiber.StatusConflict).SendString("Booking is not awaiting payment")
+	case errors.Is(err, model.ErrPaymentAmountMismatch):
+		return c.Status(fiber.StatusConflict).SendString("Paid amount does not match the booking")
+	case errors.Is(err, model.ErrInvalidTransition):
+		return c.Status(fiber.StatusConflict).SendString(err.Error())
+	case errors.Is(err, model.ErrStatusConflict):
+		return c.Status(fiber.StatusConflict).SendString("Booking was changed concurrently, try again")
+	case errors.Is(err, model.ErrWorkflowUnavailable), errors.Is(err, model.ErrPersistenceFailed):
+		return c.Status(fiber.StatusServiceUnavailable).SendString("Payment could not be processed, try again")
+	}
+	return c.Status(fiber.StatusInternalServerError).SendString("Internal Server Error")
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"booking-engine/internal/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetSeatMap handles the GET /flights/:id/seats endpoint
+func (h *Handler) GetSeatMap(c *fiber.Ctx) error {
+	seatMap, err := h.Usecase.GetSeatMap(c.Params("id"))
+	if err != nil {
+		switch err {
+		case model.ErrFlightNotFound:
+			return c.Status(fiber.StatusNotFound).SendString("Flight not found")
+		case model.ErrSeatLayoutNotFound:
+			return c.Status(fiber.StatusNotFound).SendString("Seat layout not found")
+		}
+		return c.Status(fiber.StatusInternalServerError).SendString("Internal Server Error")
+	}
+
+	return c.JSON(seatMap)
+}
+
+// UpsertSeatLayout handles the POST /seat-layouts endpoint
+func (h *Handler) UpsertSeatLayout(c *fiber.Ctx) error {
+	var request model.SeatLayout
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request format")
+	}
+
+	layout, err := h.Usecase.UpsertSeatLayout(request)
+	if err != nil {
+		if err == model.ErrInvalidSeatLayout {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid seat layout")
+		}
+		return c.Status(fiber.StatusInternalServerError).SendString("Internal Server Error")
+	}
+
+	return c.JSON(layout)
+}
+
+// HoldSeat handles the POST /flights/:id/seats/:seat/hold endpoint
+func (h *Handler) HoldSeat(c *fiber.Ctx) error {
+	var request model.SeatHoldRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request format")
+	}
+
+	hold, err := h.Usecase.HoldSeat(c.Params("id"), c.Params("seat"), request.HolderID)
+	if err != nil {
+		return seatHoldError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(hold)
+}
+
+// ReleaseSeatHold handles the DELETE /flights/:id/seats/:seat/hold endpoint
+func (h *Handler) ReleaseSeatHold(c *fiber.Ctx) error {
+	var request model.SeatHoldRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request format")
+	}
+
+	if err := h.Usecase.ReleaseSeatHold(c.Params("id"), c.Params("seat"), request.HolderID); err != nil {
+		return seatHoldError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func seatHoldError(c *fiber.Ctx, err error) error {
+	switch err {
+	case model.ErrInvalidHolder:
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid holder")
+	case model.ErrFlightNotFound:
+		return c.Status(fiber.StatusNotFound).SendString("Flight not found")
+	case model.ErrSeatLayoutNotFound:
+		return c.Status(fiber.StatusNotFound).SendString("Seat layout not found")
+	case model.ErrSeatNotFound:
+		return c.Status(fiber.StatusNotFound).SendString("Seat does not exist")
+	case model.ErrSeatHoldNotFound:
+		return c.Status(fiber.StatusNotFound).SendString("Seat hold not found")
+	case model.ErrSeatUnavailable, model.ErrSeatTaken:
+		return c.Status(fiber.StatusConflict).SendString("Seat is not available")
+	case model.ErrSeatHeld:
+		return c.Status(fiber.StatusConflict).SendString("Seat is held by another customer")
+	}
+	return c.Status(fiber.StatusInternalServerError).SendString("Internal Server Error")
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	PaymentUnpaid            = "UNPAID"
+	PaymentPaid              = "PAID"
+	PaymentRefunded          = "REFUNDED"
+	PaymentPartiallyRefunded = "PARTIALLY_REFUNDED"
+)
+
+// BookingLookupRequest represents the query parameters of a manage-my-booking lookup
+type BookingLookupRequest struct {
+	Locator  string `query:"locator"`
+	LastName string `query:"last_name"`
+}
+
+// BookingDetail represents a reservation together with the flight it was booked on
+type BookingDetail struct {
+	Reservation Reservation `json:"reservation"`
+	Flight      Flight      `json:"flight"`
+}
+
+// ReservationListRequest represents the query parameters of GET /bookings
+type ReservationListRequest struct {
+	Cursor       string `query:"cursor"`
+	Limit        int    `query:"limit"`
+	FlightNumber string `query:"flight_number"`
+	PassengerID  int    `query:"passenger_id"`
+	Status       string `query:"status"`
+	CreatedFrom  string `query:"created_from"`
+	CreatedTo    string `query:"created_to"`
+	Order        string `query:"order"`
+}
+
+// ReservationFilter is the normalized form of a reservation listing used by the repository
+type ReservationFilter struct {
+	FlightNumber string
+	PassengerID  int
+	Status       string
+	CreatedFrom  time.Time
+	CreatedTo    time.Time
+	AfterID      int
+	SortDesc     bool
+	Limit        int
+}
+
+// ReservationPage represents a page of reservations and the cursor of the next page
+type ReservationPage struct {
+	Data       []Reservation `json:"data"`
+	NextCursor string        `json:"next_cursor"`
+	Limit      int           `json:"limit"`
+	Total      int           `json:"total"`
+}
+
+var (
+	ErrReservationNotFound = errors.New("reservation not found")
+	ErrInvalidLookup       = errors.New("invalid booking lookup")
+	ErrTooManyAttempts     = errors.New("too many attempts")
+	ErrInvalidListRequest  = errors.New("invalid reservation list request")
+)
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DomainError is a failed usecase step, classified by the kind of failure and keeping its cause.
+// errors.Is matches both its kind and, through Unwrap, the sentinel it was caused by.
+type DomainError struct {
+	Kind error
+	Op   string
+	Err  error
+}
+
+func (e *DomainError) Error() string {
+	return fmt.Sprintf("%s: %s: %v", e.Op, e.Kind, e.Err)
+}
+
+// Is makes errors.Is(err, e.Kind) match the error
+func (e *DomainError) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *DomainError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	ErrValidationFailed    = errors.New("validation failed")
+	ErrPersistenceFailed   = errors.New("persistence failed")
+	ErrWorkflowUnavailable = errors.New("workflow engine unavailable")
+)
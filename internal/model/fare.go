@@ -0,0 +1,36 @@
+package model
+
+import "errors"
+
+const (
+	PassengerAdult  = "adult"
+	PassengerChild  = "child"
+	PassengerInfant = "infant"
+
+	CurrencyIDR = "IDR"
+)
+
+// FareRequest represents the query parameters of a fare quote
+type FareRequest struct {
+	FareClass     string `query:"fare_class"`
+	PassengerType string `query:"passenger_type"`
+}
+
+// Fare represents the server computed price of one passenger on a flight
+type Fare struct {
+	FlightNumber  string  `json:"flight_number"`
+	FareClass     string  `json:"fare_class"`
+	PassengerType string  `json:"passenger_type"`
+	BaseFare      float64 `json:"base_fare"`
+	AirportTax    float64 `json:"airport_tax"`
+	ServiceFee    float64 `json:"service_fee"`
+	VAT           float64 `json:"vat"`
+	Total         float64 `json:"total"`
+	Currency      string  `json:"currency"`
+}
+
+var (
+	ErrInvalidFareClass     = errors.New("invalid fare class")
+	ErrInvalidPassengerType = errors.New("invalid passenger type")
+	ErrPriceMismatch        = errors.New("price does not match the current fare")
+)
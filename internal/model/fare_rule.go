@@ -0,0 +1,21 @@
+package model
+
+import "errors"
+
+// FareRule holds the cancellation and change conditions of a fare class
+type FareRule struct {
+	Refundable       bool              `json:"refundable"`
+	CancellationFees []CancellationFee `json:"cancellation_fees"`
+	ChangeFee        float64           `json:"change_fee"`
+}
+
+// CancellationFee is the share of the fare kept when cancelling at least MinHoursBefore hours before departure
+type CancellationFee struct {
+	MinHoursBefore float64 `json:"min_hours_before"`
+	Percent        float64 `json:"percent"`
+}
+
+var (
+	ErrFlightDeparted  = errors.New("flight has already departed")
+	ErrInvalidFareRule = errors.New("invalid fare rule")
+)
@@ -7,39 +7,137 @@ import (
 
 // Flight represents a flight entity
 type Flight struct {
+	FlightID       int       `json:"flight_id"`
 	FlightNumber   string    `json:"flight_number"`
 	Departure      string    `json:"departure"`
 	Destination    string    `json:"destination"`
+	AircraftType   string    `json:"aircraft_type"`
 	DepartureTime  time.Time `json:"departure_time"`
 	Price          float64   `json:"price"`
 	AvailableSeats int       `json:"available_seats"`
 }
 
-// Booking represents a booking entity
+// FlightSearchRequest represents the query parameters of a flight search
+type FlightSearchRequest struct {
+	From         string  `query:"from"`
+	To           string  `query:"to"`
+	Date         string  `query:"date"`
+	Passengers   int     `query:"passengers"`
+	MaxPrice     float64 `query:"max_price"`
+	DepartAfter  string  `query:"depart_after"`
+	DepartBefore string  `query:"depart_before"`
+	Sort         string  `query:"sort"`
+	Order        string  `query:"order"`
+	Page         int     `query:"page"`
+	Limit        int     `query:"limit"`
+}
+
+// FlightFilter is the normalized form of a flight search used by the repository
+type FlightFilter struct {
+	From          string
+	To            string
+	DepartureFrom time.Time
+	DepartureTo   time.Time
+	MinSeats      int
+	MaxPrice      float64
+	SortBy        string
+	SortDesc      bool
+	Limit         int
+	Offset        int
+}
+
+// FlightSearchResult represents a page of flight search results
+type FlightSearchResult struct {
+	Data  []Flight `json:"data"`
+	Page  int      `json:"page"`
+	Limit int      `json:"limit"`
+	Total int      `json:"total"`
+}
+
+const (
+	FlightSortPrice         = "price"
+	FlightSortDepartureTime = "departure_time"
+)
+
+// Booking represents a booking entity, one reservation is one PNR covering
+// every passenger of the booking. PassengerID and SeatNumber are those of the
+// lead passenger and Price is the total of all passengers.
 type Reservation struct {
-	ReservationID int       `json:"reservation_id"`
-	FlightNumber  string    `json:"flight_number"`
-	PassengerID   int       `json:"passenge_idr"`
-	SeatNumber    string    `json:"seat_number"`
-	Price         float64   `json:"price"`
-	CreatedAt     time.Time `json:"create_at"`
+	ReservationID int                    `json:"reservation_id"`
+	Locator       string                 `json:"locator"`
+	FlightNumber  string                 `json:"flight_number"`
+	PassengerID   int                    `json:"passenge_idr"`
+	SeatNumber    string                 `json:"seat_number"`
+	Price         float64                `json:"price"`
+	RefundAmount  float64                `json:"refund_amount"`
+	PaymentStatus string                 `json:"payment_status"`
+	TransactionID string                 `json:"transaction_id"`
+	PaymentRef    string                 `json:"payment_reference"`
+	Status        string                 `json:"status"`
+	InstanceKey   int64                  `json:"instance_key"`
+	CreatedAt     time.Time              `json:"create_at"`
+	PaidAt        *time.Time             `json:"paid_at,omitempty"`
+	TicketedAt    *time.Time             `json:"ticketed_at,omitempty"`
+	CancelledAt   *time.Time             `json:"cancelled_at,omitempty"`
+	ExpiredAt     *time.Time             `json:"expired_at,omitempty"`
+	Passengers    []ReservationPassenger `json:"passengers"`
+}
+
+// ReservationPassenger represents one passenger and seat of a reservation
+type ReservationPassenger struct {
+	PassengerID   int    `json:"passenger_id"`
+	FirstName     string `json:"first_name"`
+	LastName      string `json:"last_name"`
+	PassengerType string `json:"passenger_type"`
+	SeatNumber    string `json:"seat_number"`
+	FareClass     string `json:"fare_class"`
+	// FareBasis is the fare class the price was paid for, whose fare rules apply. A downgraded passenger
+	// keeps the fare basis of the higher class since downgrades are not refunded.
+	FareBasis string  `json:"fare_basis"`
+	Price     float64 `json:"price"`
 }
 
-// BookingRequest represents the request structure for booking a flight
+// BookingRequest represents the request structure for booking a flight. The
+// top level passenger fields are a shorthand for a single passenger booking
+// and cannot be combined with Passengers.
 type BookingRequest struct {
-	FlightNumber string  `json:"flight_number"`
-	PassengerID  int     `json:"passenger_id"`
-	SeatNumber   string  `json:"seat_number"`
-	Price        float64 `json:"price"`
+	FlightNumber  string             `json:"flight_number"`
+	Passengers    []BookingPassenger `json:"passengers"`
+	PassengerID   int                `json:"passenger_id"`
+	PassengerType string             `json:"passenger_type"`
+	SeatNumber    string             `json:"seat_number"`
+	FareClass     string             `json:"fare_class"`
+	Price         float64            `json:"price"`
+	HolderID      string             `json:"holder_id"`
+	QuoteToken    string             `json:"quote_token"`
+}
+
+// BookingPassenger represents one passenger and seat of a booking request,
+// infants may leave SeatNumber empty to travel on an adult's lap
+type BookingPassenger struct {
+	PassengerID   int     `json:"passenger_id"`
+	FirstName     string  `json:"-"`
+	LastName      string  `json:"-"`
+	PassengerType string  `json:"passenger_type"`
+	SeatNumber    string  `json:"seat_number"`
+	FareClass     string  `json:"fare_class"`
+	Price         float64 `json:"price"`
 }
 
 // Variable BPMN
 
 type BookingVariables struct {
-	ReservationID int  `json:"reservation_id"`
-	StatusPayment bool `json:"status_payment"`
+	ReservationID int    `json:"reservation_id"`
+	Locator       string `json:"locator"`
+	StatusPayment bool   `json:"status_payment"`
 }
 
 var (
 	ErrFlightNotFound = errors.New("flight not found")
+	ErrInvalidFlight  = errors.New("invalid flight")
+	ErrInvalidSearch  = errors.New("invalid search parameters")
+	ErrFlightSoldOut  = errors.New("flight sold out")
+	ErrInvalidBooking = errors.New("invalid booking request")
+
+	ErrDuplicateLocator = errors.New("record locator already in use")
 )
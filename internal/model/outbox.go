@@ -0,0 +1,19 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WorkflowOutboxEntry is a process instance that still has to be started for a reservation
+type WorkflowOutboxEntry struct {
+	OutboxID      int
+	ReservationID int
+	ProcessID     string
+	// ProcessVersion pins the version of the process, zero starts its latest version
+	ProcessVersion int32
+	Variables      json.RawMessage
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastError      string
+}
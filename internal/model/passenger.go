@@ -0,0 +1,36 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	GenderMale   = "male"
+	GenderFemale = "female"
+
+	DocumentPassport   = "passport"
+	DocumentNationalID = "national_id"
+)
+
+// Passenger represents a passenger profile
+type Passenger struct {
+	PassengerID    int       `json:"passenger_id"`
+	FirstName      string    `json:"first_name"`
+	LastName       string    `json:"last_name"`
+	DateOfBirth    string    `json:"date_of_birth"`
+	Gender         string    `json:"gender"`
+	Email          string    `json:"email"`
+	Phone          string    `json:"phone"`
+	Nationality    string    `json:"nationality"`
+	DocumentType   string    `json:"document_type"`
+	DocumentNumber string    `json:"document_number"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+var (
+	ErrPassengerNotFound = errors.New("passenger not found")
+	ErrInvalidPassenger  = errors.New("invalid passenger")
+	ErrPassengerInUse    = errors.New("passenger has reservations")
+)
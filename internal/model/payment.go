@@ -0,0 +1,87 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	PaymentCallbackPaid   = "PAID"
+	PaymentCallbackFailed = "FAILED"
+
+	// MessagePaymentReceived is the BPMN message the fww-bpm process waits on for payment,
+	// correlated by reservation ID
+	MessagePaymentReceived = "payment-received"
+)
+
+const (
+	PaymentMethodVirtualAccount = "virtual_account"
+	PaymentMethodQRIS           = "qris"
+	PaymentMethodEWallet        = "ewallet"
+	PaymentMethodCard           = "card"
+
+	ChargePending   = "PENDING"
+	ChargeSucceeded = "SUCCEEDED"
+	ChargeFailed    = "FAILED"
+	ChargeRefunded  = "REFUNDED"
+	ChargeVoided    = "VOIDED"
+)
+
+// PaymentRequest represents the request structure for paying a reservation
+type PaymentRequest struct {
+	Method string `json:"method"`
+	// Channel is the bank of a virtual account or the provider of an e-wallet, e.g. "bca" or "ovo"
+	Channel string `json:"channel"`
+}
+
+// ChargeRequest represents a charge to be created at a payment provider
+type ChargeRequest struct {
+	Reference string
+	Amount    float64
+	Currency  string
+	Method    string
+	Channel   string
+}
+
+// Charge represents a charge at a payment provider and the instructions to pay it
+type Charge struct {
+	ChargeID             string    `json:"charge_id"`
+	Reference            string    `json:"reference"`
+	Method               string    `json:"method"`
+	Channel              string    `json:"channel,omitempty"`
+	Status               string    `json:"status"`
+	Amount               float64   `json:"amount"`
+	Currency             string    `json:"currency"`
+	VirtualAccountNumber string    `json:"virtual_account_number,omitempty"`
+	QRString             string    `json:"qr_string,omitempty"`
+	RedirectURL          string    `json:"redirect_url,omitempty"`
+	CreatedAt            time.Time `json:"created_at"`
+	ExpiresAt            time.Time `json:"expires_at"`
+}
+
+// Refund represents money returned for a charge
+type Refund struct {
+	RefundID string  `json:"refund_id"`
+	ChargeID string  `json:"charge_id"`
+	Amount   float64 `json:"amount"`
+	Status   string  `json:"status"`
+}
+
+// PaymentCallback represents the notification sent by the payment gateway when a payment settles
+type PaymentCallback struct {
+	ReservationID int     `json:"reservation_id"`
+	TransactionID string  `json:"transaction_id"`
+	Status        string  `json:"status"`
+	Amount        float64 `json:"amount"`
+}
+
+var (
+	ErrInvalidSignature      = errors.New("invalid callback signature")
+	ErrInvalidCallback       = errors.New("invalid payment callback")
+	ErrPaymentAmountMismatch = errors.New("paid amount does not match the reservation")
+	ErrInvalidPaymentMethod  = errors.New("invalid payment method")
+	ErrChargeNotFound        = errors.New("charge not found")
+	ErrNotPayable            = errors.New("reservation is not awaiting payment")
+	ErrRefundExceedsCharge   = errors.New("refund exceeds the charged amount")
+	ErrChargeNotVoidable     = errors.New("charge has already been paid")
+)
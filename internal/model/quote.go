@@ -0,0 +1,61 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// QuoteRequest represents the request structure for quoting a fare.
+// A mixed group lists its passenger types in Breakdown, otherwise PassengerType and Passengers describe the whole group.
+// All passengers of a quote fly in the same fare class.
+type QuoteRequest struct {
+	FlightNumber  string           `json:"flight_number"`
+	FareClass     string           `json:"fare_class"`
+	PassengerType string           `json:"passenger_type"`
+	Passengers    int              `json:"passengers"`
+	Breakdown     []QuotePassenger `json:"breakdown"`
+}
+
+// QuotePassenger represents the number of passengers of one type in a quote request
+type QuotePassenger struct {
+	PassengerType string `json:"passenger_type"`
+	Count         int    `json:"count"`
+}
+
+// QuotedFare represents the fare of one passenger type and how many passengers it covers
+type QuotedFare struct {
+	Fare
+	Count int `json:"count"`
+}
+
+// QuoteClaims is the signed content of a quote token
+type QuoteClaims struct {
+	FlightNumber string       `json:"flight_number"`
+	Fares        []QuotedFare `json:"fares"`
+	Passengers   int          `json:"passengers"`
+	Total        float64      `json:"total"`
+	ExpiresAt    time.Time    `json:"expires_at"`
+}
+
+// FareFor returns the quoted fare of a passenger type
+func (q QuoteClaims) FareFor(passengerType string) (Fare, bool) {
+	for _, fare := range q.Fares {
+		if fare.PassengerType == passengerType {
+			return fare.Fare, true
+		}
+	}
+	return Fare{}, false
+}
+
+// Quote represents a guaranteed price that can be redeemed when booking until it expires
+type Quote struct {
+	QuoteClaims
+	Token string `json:"token"`
+}
+
+var (
+	ErrInvalidQuote  = errors.New("invalid quote request")
+	ErrQuoteInvalid  = errors.New("quote token is invalid")
+	ErrQuoteExpired  = errors.New("quote token has expired")
+	ErrQuoteMismatch = errors.New("quote does not match the booking")
+)
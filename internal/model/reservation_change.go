@@ -0,0 +1,58 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// ModifyBookingRequest represents the request structure for changing the seats or flight of a booking.
+// When FlightNumber names another flight every seated passenger needs a seat on it in Seats.
+// A change that costs extra on a paid booking is paid through Payment, or through ChargeID once that charge is paid.
+type ModifyBookingRequest struct {
+	FlightNumber string          `json:"flight_number"`
+	Seats        []SeatChange    `json:"seats"`
+	HolderID     string          `json:"holder_id"`
+	Payment      *PaymentRequest `json:"payment,omitempty"`
+	ChargeID     string          `json:"charge_id,omitempty"`
+}
+
+// SeatChange represents the new seat requested for one passenger
+type SeatChange struct {
+	PassengerID int    `json:"passenger_id"`
+	SeatNumber  string `json:"seat_number"`
+}
+
+// ReservationChange represents one modification in the change history of a reservation
+type ReservationChange struct {
+	ChangeID        int               `json:"change_id"`
+	ReservationID   int               `json:"reservation_id"`
+	OldFlightNumber string            `json:"old_flight_number"`
+	NewFlightNumber string            `json:"new_flight_number"`
+	Passengers      []PassengerChange `json:"passengers"`
+	FareDifference  float64           `json:"fare_difference"`
+	ChangeFee       float64           `json:"change_fee"`
+	AmountDue       float64           `json:"amount_due"`
+	ChargeID        string            `json:"charge_id,omitempty"`
+	CreatedAt       time.Time         `json:"created_at"`
+	// Charge is the charge still to be paid before the change applies, or the new charge of a pending booking
+	Charge *Charge `json:"charge,omitempty"`
+}
+
+// PassengerChange represents the seat and fare of one passenger before and after a modification
+type PassengerChange struct {
+	PassengerID  int     `json:"passenger_id"`
+	OldSeat      string  `json:"old_seat"`
+	NewSeat      string  `json:"new_seat"`
+	OldFareClass string  `json:"old_fare_class"`
+	NewFareClass string  `json:"new_fare_class"`
+	FareBasis    string  `json:"fare_basis"`
+	OldPrice     float64 `json:"old_price"`
+	NewPrice     float64 `json:"new_price"`
+}
+
+var (
+	ErrInvalidModification  = errors.New("invalid booking modification")
+	ErrBookingNotModifiable = errors.New("booking can no longer be modified")
+	ErrChangeNotPaid        = errors.New("change of a paid booking costs extra and needs a payment")
+	ErrChargeAlreadyUsed    = errors.New("charge already paid for another change")
+)
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	ReservationPending   = "PENDING"
+	ReservationPaid      = "PAID"
+	ReservationTicketed  = "TICKETED"
+	ReservationCancelled = "CANCELLED"
+	ReservationExpired   = "EXPIRED"
+)
+
+// StatusChangeRequest represents the request structure for changing a reservation status
+type StatusChangeRequest struct {
+	Status string `json:"status"`
+}
+
+// InvalidTransitionError reports a status change the reservation state machine does not allow
+type InvalidTransitionError struct {
+	From string
+	To   string
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("reservation cannot go from %s to %s", e.From, e.To)
+}
+
+// Is makes errors.Is(err, ErrInvalidTransition) match any InvalidTransitionError
+func (e *InvalidTransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+var (
+	ErrInvalidTransition = errors.New("invalid reservation status transition")
+	ErrUnknownStatus     = errors.New("unknown reservation status")
+	ErrStatusConflict    = errors.New("reservation status was changed concurrently")
+	ErrStatusNotSettable = errors.New("reservation status can only be set to TICKETED directly")
+)
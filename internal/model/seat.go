@@ -0,0 +1,72 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	CabinEconomy  = "economy"
+	CabinBusiness = "business"
+)
+
+// Cabin represents a contiguous block of rows sharing the same cabin class
+type Cabin struct {
+	Class    string `json:"class"`
+	FirstRow int    `json:"first_row"`
+	LastRow  int    `json:"last_row"`
+}
+
+// SeatLayout represents the seat configuration of an aircraft type
+type SeatLayout struct {
+	AircraftType string   `json:"aircraft_type"`
+	Rows         int      `json:"rows"`
+	Columns      string   `json:"columns"`
+	Cabins       []Cabin  `json:"cabins"`
+	ExitRows     []int    `json:"exit_rows"`
+	BlockedSeats []string `json:"blocked_seats"`
+}
+
+// Seat represents a single seat of a flight's seat map
+type Seat struct {
+	SeatNumber string `json:"seat_number"`
+	Row        int    `json:"row"`
+	Column     string `json:"column"`
+	Cabin      string `json:"cabin"`
+	ExitRow    bool   `json:"exit_row"`
+	Blocked    bool   `json:"blocked"`
+	Occupied   bool   `json:"occupied"`
+}
+
+// SeatMap represents the seats of a flight and whether they are free
+type SeatMap struct {
+	FlightNumber  string `json:"flight_number"`
+	AircraftType  string `json:"aircraft_type"`
+	TotalSeats    int    `json:"total_seats"`
+	OccupiedSeats int    `json:"occupied_seats"`
+	Seats         []Seat `json:"seats"`
+}
+
+// SeatHoldRequest represents the request structure for holding or releasing a seat
+type SeatHoldRequest struct {
+	HolderID string `json:"holder_id"`
+}
+
+// SeatHold represents a temporary claim of a seat by a customer before payment
+type SeatHold struct {
+	FlightNumber string    `json:"flight_number"`
+	SeatNumber   string    `json:"seat_number"`
+	HolderID     string    `json:"holder_id"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+var (
+	ErrSeatLayoutNotFound = errors.New("seat layout not found")
+	ErrInvalidSeatLayout  = errors.New("invalid seat layout")
+	ErrSeatNotFound       = errors.New("seat not found")
+	ErrSeatUnavailable    = errors.New("seat is blocked")
+	ErrSeatTaken          = errors.New("seat already taken")
+	ErrSeatHeld           = errors.New("seat is held by another customer")
+	ErrSeatHoldNotFound   = errors.New("seat hold not found")
+	ErrInvalidHolder      = errors.New("invalid seat holder")
+)
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+const WorkflowNotStarted = "NOT_STARTED"
+
+// ProcessDefinition identifies the BPMN process new instances are started from, a zero Version means its latest version
+type ProcessDefinition struct {
+	ProcessID string
+	Version   int32
+}
+
+// WorkflowDeployment records a process deployed from one of the embedded workflow resources
+type WorkflowDeployment struct {
+	ResourceName string    `json:"resource_name"`
+	ProcessID    string    `json:"process_id"`
+	Checksum     string    `json:"checksum"`
+	Version      int32     `json:"version"`
+	ProcessKey   int64     `json:"process_key"`
+	DeployedAt   time.Time `json:"deployed_at"`
+}
+
+// WorkflowStatus is where a reservation stands in its process
+type WorkflowStatus struct {
+	ReservationID  int                    `json:"reservation_id"`
+	InstanceKey    int64                  `json:"instance_key"`
+	ProcessID      string                 `json:"process_id"`
+	Version        int32                  `json:"version,omitempty"`
+	State          string                 `json:"state"`
+	ActiveElements []string               `json:"active_elements"`
+	Incidents      []string               `json:"incidents"`
+	Variables      map[string]interface{} `json:"variables"`
+	// Outbox is set while the instance has not been started yet
+	Outbox *WorkflowOutboxStatus `json:"outbox,omitempty"`
+}
+
+// WorkflowOutboxStatus is how starting the process instance of a reservation has gone so far
+type WorkflowOutboxStatus struct {
+	Attempts      int       `json:"attempts"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	LastError     string    `json:"last_error,omitempty"`
+}
+
+var (
+	ErrWorkflowInstanceNotFound = errors.New("process instance not found")
+	ErrWorkflowNoInspection     = errors.New("process instances cannot be inspected")
+)
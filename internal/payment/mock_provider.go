@@ -0,0 +1,174 @@
+package payment
+
+import (
+	"booking-engine/internal/model"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	MockScenarioSuccess = "success"
+	MockScenarioFailure = "failure"
+	MockScenarioDelayed = "delayed"
+
+	DefaultMockSettlementDelay = 30 * time.Second
+	mockChargeExpiry           = 24 * time.Hour
+)
+
+// MockProvider is an in-process payment provider for local development. Depending on its scenario every charge
+// settles at once, fails at once, or stays pending for SettlementDelay before settling.
+type MockProvider struct {
+	Scenario        string
+	SettlementDelay time.Duration
+
+	mu      sync.Mutex
+	charges map[string]*model.Charge
+	refunds map[string]float64
+}
+
+// NewMockProvider creates a new instance of the mock payment provider
+func NewMockProvider(scenario string, settlementDelay time.Duration) PaymentProvider {
+	if scenario == "" {
+		scenario = MockScenarioSuccess
+	}
+	if settlementDelay <= 0 {
+		settlementDelay = DefaultMockSettlementDelay
+	}
+
+	return &MockProvider{
+		Scenario:        scenario,
+		SettlementDelay: settlementDelay,
+		charges:         map[string]*model.Charge{},
+		refunds:         map[string]float64{},
+	}
+}
+
+// CreateCharge records a charge and returns payment instructions matching its method
+func (p *MockProvider) CreateCharge(ctx context.Context, request model.ChargeRequest) (model.Charge, error) {
+	if !ValidMethod(request.Method) {
+		return model.Charge{}, model.ErrInvalidPaymentMethod
+	}
+
+	id, err := randomID()
+	if err != nil {
+		return model.Charge{}, err
+	}
+	now := time.Now()
+	charge := &model.Charge{
+		ChargeID:  "mock-" + id,
+		Reference: request.Reference,
+		Method:    request.Method,
+		Channel:   request.Channel,
+		Status:    model.ChargePending,
+		Amount:    request.Amount,
+		Currency:  request.Currency,
+		CreatedAt: now,
+		ExpiresAt: now.Add(mockChargeExpiry),
+	}
+
+	switch request.Method {
+	case model.PaymentMethodVirtualAccount:
+		charge.VirtualAccountNumber = "8808" + id[:12]
+	case model.PaymentMethodQRIS:
+		charge.QRString = fmt.Sprintf("00020101021226MOCKQRIS%s5303360540%.0f6304", id, request.Amount)
+	case model.PaymentMethodEWallet, model.PaymentMethodCard:
+		charge.RedirectURL = "https://mock-payment.local/pay/" + charge.ChargeID
+	}
+
+	switch p.Scenario {
+	case MockScenarioSuccess:
+		charge.Status = model.ChargeSucceeded
+	case MockScenarioFailure:
+		charge.Status = model.ChargeFailed
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.charges[charge.ChargeID] = charge
+
+	return *charge, nil
+}
+
+// QueryStatus returns the current state of a charge, settling delayed charges once their delay has passed
+func (p *MockProvider) QueryStatus(ctx context.Context, chargeID string) (model.Charge, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	charge, ok := p.charges[chargeID]
+	if !ok {
+		return model.Charge{}, model.ErrChargeNotFound
+	}
+	p.settle(charge)
+
+	return *charge, nil
+}
+
+// Refund returns part or all of a settled charge
+func (p *MockProvider) Refund(ctx context.Context, chargeID string, amount float64) (model.Refund, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	charge, ok := p.charges[chargeID]
+	if !ok {
+		return model.Refund{}, model.ErrChargeNotFound
+	}
+	if charge.Status != model.ChargeSucceeded && charge.Status != model.ChargeRefunded {
+		return model.Refund{}, model.ErrNotPayable
+	}
+	if p.refunds[chargeID]+amount > charge.Amount {
+		return model.Refund{}, model.ErrRefundExceedsCharge
+	}
+
+	id, err := randomID()
+	if err != nil {
+		return model.Refund{}, err
+	}
+	p.refunds[chargeID] += amount
+	charge.Status = model.ChargeRefunded
+
+	return model.Refund{
+		RefundID: "mock-refund-" + id,
+		ChargeID: chargeID,
+		Amount:   amount,
+		Status:   model.ChargeSucceeded,
+	}, nil
+}
+
+// Void cancels a charge that has not been paid, voiding a failed or voided charge changes nothing
+func (p *MockProvider) Void(ctx context.Context, chargeID string) (model.Charge, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	charge, ok := p.charges[chargeID]
+	if !ok {
+		return model.Charge{}, model.ErrChargeNotFound
+	}
+	p.settle(charge)
+	switch charge.Status {
+	case model.ChargeSucceeded, model.ChargeRefunded:
+		return model.Charge{}, model.ErrChargeNotVoidable
+	case model.ChargePending:
+		charge.Status = model.ChargeVoided
+	}
+
+	return *charge, nil
+}
+
+// settle marks a delayed charge paid once its delay has passed
+func (p *MockProvider) settle(charge *model.Charge) {
+	if charge.Status == model.ChargePending && time.Since(charge.CreatedAt) >= p.SettlementDelay {
+		charge.Status = model.ChargeSucceeded
+	}
+}
+
+func randomID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
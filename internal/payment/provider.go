@@ -0,0 +1,24 @@
+package payment
+
+import (
+	"booking-engine/internal/model"
+	"context"
+)
+
+// PaymentProvider is implemented by every payment gateway the booking flow can charge through
+type PaymentProvider interface {
+	CreateCharge(ctx context.Context, request model.ChargeRequest) (model.Charge, error)
+	QueryStatus(ctx context.Context, chargeID string) (model.Charge, error)
+	Refund(ctx context.Context, chargeID string, amount float64) (model.Refund, error)
+	// Void cancels a charge that has not been paid, so it can no longer be paid
+	Void(ctx context.Context, chargeID string) (model.Charge, error)
+}
+
+// ValidMethod reports whether the method is one of the supported payment methods
+func ValidMethod(method string) bool {
+	switch method {
+	case model.PaymentMethodVirtualAccount, model.PaymentMethodQRIS, model.PaymentMethodEWallet, model.PaymentMethodCard:
+		return true
+	}
+	return false
+}
@@ -3,6 +3,9 @@ package repository
 import (
 	"booking-engine/internal/model"
 	"database/sql"
+	"fmt"
+	"strings"
+	"time"
 )
 
 type FlightRepository struct {
@@ -10,10 +13,34 @@ type FlightRepository struct {
 }
 
 type FlightPersister interface {
-	SaveBooking(booking model.BookingRequest) (reservationID int, err error)
-	GetAllReservations() ([]model.Reservation, error)
+	GetFlightByID(flightID int) (model.Flight, error)
+	GetFlightByNumber(flightNumber string) (model.Flight, error)
+	SearchFlights(filter model.FlightFilter) (flights []model.Flight, total int, err error)
+	UpsertFlight(flight model.Flight) (flightID int, err error)
+	SaveBooking(locator string, booking model.BookingRequest, process model.ProcessDefinition) (reservationID int, err error)
+	LocatorExists(locator string) (bool, error)
+	GetReservationByLocator(locator string) (model.Reservation, error)
+	GetAllReservations(filter model.ReservationFilter) (reservations []model.Reservation, total int, err error)
 	GetBookingByID(bookingID int) (model.Reservation, error)
 	UpdateInstanceID(reservationID int, instanceKey int64) error
+	UpdateReservationStatus(reservationID int, from string, to string, at time.Time) error
+	CloseReservation(reservationID int, from string, to string, refundAmount float64, at time.Time) (int64, error)
+	GetExpirableReservations(createdBefore time.Time, limit int) ([]model.Reservation, error)
+	MarkReservationPaid(reservationID int, transactionID string, at time.Time) error
+	UpdatePaymentReference(reservationID int, paymentReference string) error
+	UpdatePaymentStatus(reservationID int, paymentStatus string) error
+	ModifyReservation(status string, change model.ReservationChange) (changeID int, err error)
+	GetReservationChanges(reservationID int) ([]model.ReservationChange, error)
+	GetPendingOutboxEntries(now time.Time, limit int) ([]model.WorkflowOutboxEntry, error)
+	ClaimOutboxEntry(entry model.WorkflowOutboxEntry, retryAt time.Time) (bool, error)
+	RecordOutboxError(outboxID int, message string) error
+	MarkOutboxDispatched(outboxID int, at time.Time) error
+	GetOutboxEntry(reservationID int) (model.WorkflowOutboxEntry, error)
+	GetWorkflowDeployments(resourceName string) ([]model.WorkflowDeployment, error)
+	RecordWorkflowDeployments(deployments []model.WorkflowDeployment) error
+	GetSeatLayout(aircraftType string) (model.SeatLayout, error)
+	UpsertSeatLayout(layout model.SeatLayout) error
+	GetOccupiedSeats(flightNumber string) ([]string, error)
 }
 
 // NewFlightRepository creates a new instance of FlightRepository
@@ -21,11 +48,95 @@ func NewFlightRepository(flight FlightRepository) FlightPersister {
 	return &flight
 }
 
-// SaveBooking saves a new booking to the MySQL database
-func (r *FlightRepository) SaveBooking(booking model.BookingRequest) (reservationID int, err error) {
-	query := "INSERT INTO reservations (flight_number, passenger_id, seat_number, price, created_at) VALUES (?, ?, ?, ?, NOW())"
-	result, err := r.DB.Exec(query, booking.FlightNumber, booking.PassengerID, booking.SeatNumber, booking.Price)
+const flightColumns = "flight_id, flight_number, departure, destination, aircraft_type, departure_time, price, available_seats"
 
+// GetFlightByID retrieves a flight by its ID from the MySQL database
+func (r *FlightRepository) GetFlightByID(flightID int) (model.Flight, error) {
+	query := "SELECT " + flightColumns + " FROM flights WHERE flight_id = ?"
+	return scanFlight(r.DB.QueryRow(query, flightID))
+}
+
+// GetFlightByNumber retrieves a flight by its flight number from the MySQL database
+func (r *FlightRepository) GetFlightByNumber(flightNumber string) (model.Flight, error) {
+	query := "SELECT " + flightColumns + " FROM flights WHERE flight_number = ?"
+	return scanFlight(r.DB.QueryRow(query, flightNumber))
+}
+
+// SearchFlights retrieves a page of flights matching the filter together with the total match count
+func (r *FlightRepository) SearchFlights(filter model.FlightFilter) (flights []model.Flight, total int, err error) {
+	where := []string{"1 = 1"}
+	args := []interface{}{}
+
+	if filter.From != "" {
+		where = append(where, "departure = ?")
+		args = append(args, filter.From)
+	}
+	if filter.To != "" {
+		where = append(where, "destination = ?")
+		args = append(args, filter.To)
+	}
+	if !filter.DepartureFrom.IsZero() {
+		where = append(where, "departure_time >= ?")
+		args = append(args, filter.DepartureFrom)
+	}
+	if !filter.DepartureTo.IsZero() {
+		where = append(where, "departure_time < ?")
+		args = append(args, filter.DepartureTo)
+	}
+	if filter.MinSeats > 0 {
+		where = append(where, "available_seats >= ?")
+		args = append(args, filter.MinSeats)
+	}
+	if filter.MaxPrice > 0 {
+		where = append(where, "price <= ?")
+		args = append(args, filter.MaxPrice)
+	}
+	conditions := strings.Join(where, " AND ")
+
+	if err := r.DB.QueryRow("SELECT COUNT(*) FROM flights WHERE "+conditions, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	// The sort column is whitelisted here, never taken from user input directly
+	orderBy := "departure_time"
+	if filter.SortBy == model.FlightSortPrice {
+		orderBy = "price"
+	}
+	direction := "ASC"
+	if filter.SortDesc {
+		direction = "DESC"
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM flights WHERE %s ORDER BY %s %s, flight_id LIMIT ? OFFSET ?",
+		flightColumns, conditions, orderBy, direction)
+	rows, err := r.DB.Query(query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	flights = []model.Flight{}
+	for rows.Next() {
+		flight, err := scanFlight(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		flights = append(flights, flight)
+	}
+
+	return flights, total, rows.Err()
+}
+
+// UpsertFlight inserts a flight or updates the existing one with the same flight number. Available seats are
+// only set on insert, afterwards bookings own the inventory.
+func (r *FlightRepository) UpsertFlight(flight model.Flight) (flightID int, err error) {
+	query := `INSERT INTO flights (flight_number, departure, destination, aircraft_type, departure_time, price, available_seats)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE flight_id = LAST_INSERT_ID(flight_id), departure = VALUES(departure),
+			destination = VALUES(destination), aircraft_type = VALUES(aircraft_type),
+			departure_time = VALUES(departure_time), price = VALUES(price)`
+	result, err := r.DB.Exec(query, flight.FlightNumber, flight.Departure, flight.Destination,
+		flight.AircraftType, flight.DepartureTime, flight.Price, flight.AvailableSeats)
 	if err != nil {
 		return 0, err
 	}
@@ -36,52 +147,475 @@ func (r *FlightRepository) SaveBooking(booking model.BookingRequest) (reservatio
 	return int(lastInsertID), nil
 }
 
-// GetBookingByID retrieves a booking by ID from the MySQL database
-func (r *FlightRepository) GetBookingByID(bookingID int) (model.Reservation, error) {
-	query := "SELECT * FROM bookings WHERE id = ?"
-	row := r.DB.QueryRow(query, bookingID)
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanFlight(row rowScanner) (model.Flight, error) {
+	var flight model.Flight
+	err := row.Scan(&flight.FlightID, &flight.FlightNumber, &flight.Departure, &flight.Destination,
+		&flight.AircraftType, &flight.DepartureTime, &flight.Price, &flight.AvailableSeats)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return flight, model.ErrFlightNotFound
+		}
+		return flight, err
+	}
+
+	return flight, nil
+}
+
+// SaveBooking saves a new booking with all of its passengers to the MySQL database,
+// claiming their seats, decrementing the flight's available seats and queueing its process instance in the same transaction
+func (r *FlightRepository) SaveBooking(locator string, booking model.BookingRequest, process model.ProcessDefinition) (reservationID int, err error) {
+	if len(booking.Passengers) == 0 {
+		return 0, model.ErrInvalidBooking
+	}
+
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
 
-	var booking model.Reservation
-	err := row.Scan(&booking.ReservationID, &booking.FlightNumber, &booking.PassengerID, &booking.SeatNumber, &booking.Price, &booking.CreatedAt)
+	seated := 0
+	for _, passenger := range booking.Passengers {
+		if passenger.SeatNumber != "" {
+			seated++
+		}
+	}
+
+	// Locking the flight row serializes concurrent bookings of the same flight
+	var availableSeats int
+	err = tx.QueryRow("SELECT available_seats FROM flights WHERE flight_number = ? FOR UPDATE", booking.FlightNumber).
+		Scan(&availableSeats)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return booking, model.ErrFlightNotFound
+			err = model.ErrFlightNotFound
+		}
+		return 0, err
+	}
+	if availableSeats < seated {
+		err = model.ErrFlightSoldOut
+		return 0, err
+	}
+	_, err = tx.Exec("UPDATE flights SET available_seats = available_seats - ? WHERE flight_number = ?", seated, booking.FlightNumber)
+	if err != nil {
+		return 0, err
+	}
+
+	// created_at is written from Go like the filters it is compared with, NOW() would follow the server time zone
+	now := time.Now()
+	lead := booking.Passengers[0]
+	query := "INSERT INTO reservations (locator, flight_number, passenger_id, seat_number, price, created_at) VALUES (?, ?, ?, ?, ?, ?)"
+	result, err := tx.Exec(query, locator, booking.FlightNumber, lead.PassengerID, lead.SeatNumber, booking.Price, now)
+	if err != nil {
+		if isDuplicateKey(err) {
+			err = model.ErrDuplicateLocator
 		}
+		return 0, err
+	}
+	lastInsertID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, passenger := range booking.Passengers {
+		_, err = tx.Exec(`INSERT INTO reservation_passengers
+			(reservation_id, passenger_id, first_name, last_name, passenger_type, seat_number, fare_class, fare_basis, price)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			lastInsertID, passenger.PassengerID, passenger.FirstName, passenger.LastName,
+			passenger.PassengerType, passenger.SeatNumber, passenger.FareClass, passenger.FareClass, passenger.Price)
+		if err != nil {
+			return 0, err
+		}
+		if passenger.SeatNumber == "" {
+			continue
+		}
+
+		// The primary key on (flight_number, seat_number) is what guarantees a seat is sold only once
+		_, err = tx.Exec("INSERT INTO flight_seats (flight_number, seat_number, reservation_id) VALUES (?, ?, ?)",
+			booking.FlightNumber, passenger.SeatNumber, lastInsertID)
+		if err != nil {
+			if isDuplicateKey(err) {
+				err = model.ErrSeatTaken
+			}
+			return 0, err
+		}
+	}
+
+	// The process instance is started from the outbox, so a booking never exists without one on the way
+	variables := model.BookingVariables{
+		ReservationID: int(lastInsertID),
+		Locator:       locator,
+	}
+	if err = insertWorkflowOutbox(tx, int(lastInsertID), process, variables, now); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+	return int(lastInsertID), nil
+}
+
+const reservationColumns = "reservation_id, COALESCE(locator, ''), flight_number, passenger_id, seat_number, price, refund_amount, " +
+	"payment_status, transaction_id, payment_reference, status, COALESCE(instance_key, 0), created_at, paid_at, ticketed_at, cancelled_at, expired_at"
+
+// GetBookingByID retrieves a reservation and its passengers by ID from the MySQL database
+func (r *FlightRepository) GetBookingByID(bookingID int) (model.Reservation, error) {
+	query := "SELECT " + reservationColumns + " FROM reservations WHERE reservation_id = ?"
+	booking, err := scanReservation(r.DB.QueryRow(query, bookingID))
+	if err != nil {
+		return booking, err
+	}
+
+	booking.Passengers, err = r.getReservationPassengers(booking.ReservationID)
+	if err != nil {
 		return booking, err
 	}
 
 	return booking, nil
 }
 
-// GetAllBookings retrieves all bookings from the MySQL database
-func (r *FlightRepository) GetAllReservations() ([]model.Reservation, error) {
-	query := "SELECT * FROM bookings"
-	rows, err := r.DB.Query(query)
+// LocatorExists reports whether a record locator is already used by a reservation
+func (r *FlightRepository) LocatorExists(locator string) (bool, error) {
+	var count int
+	if err := r.DB.QueryRow("SELECT COUNT(*) FROM reservations WHERE locator = ?", locator).Scan(&count); err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// GetReservationByLocator retrieves a reservation and its passengers by record locator
+func (r *FlightRepository) GetReservationByLocator(locator string) (model.Reservation, error) {
+	query := "SELECT " + reservationColumns + " FROM reservations WHERE locator = ?"
+	reservation, err := scanReservation(r.DB.QueryRow(query, locator))
+	if err != nil {
+		return reservation, err
+	}
+
+	reservation.Passengers, err = r.getReservationPassengers(reservation.ReservationID)
+	if err != nil {
+		return reservation, err
+	}
+
+	return reservation, nil
+}
+
+func scanReservation(row rowScanner) (model.Reservation, error) {
+	var (
+		reservation                                model.Reservation
+		paidAt, ticketedAt, cancelledAt, expiredAt sql.NullTime
+	)
+	err := row.Scan(&reservation.ReservationID, &reservation.Locator, &reservation.FlightNumber, &reservation.PassengerID,
+		&reservation.SeatNumber, &reservation.Price, &reservation.RefundAmount, &reservation.PaymentStatus, &reservation.TransactionID, &reservation.PaymentRef, &reservation.Status, &reservation.InstanceKey,
+		&reservation.CreatedAt, &paidAt, &ticketedAt, &cancelledAt, &expiredAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return reservation, model.ErrReservationNotFound
+		}
+		return reservation, err
+	}
+	reservation.PaidAt = nullTimePtr(paidAt)
+	reservation.TicketedAt = nullTimePtr(ticketedAt)
+	reservation.CancelledAt = nullTimePtr(cancelledAt)
+	reservation.ExpiredAt = nullTimePtr(expiredAt)
+
+	return reservation, nil
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func (r *FlightRepository) getReservationPassengers(reservationID int) ([]model.ReservationPassenger, error) {
+	query := `SELECT passenger_id, first_name, last_name, passenger_type, seat_number, fare_class, fare_basis, price
+		FROM reservation_passengers WHERE reservation_id = ? ORDER BY reservation_passenger_id`
+	rows, err := r.DB.Query(query, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	passengers := []model.ReservationPassenger{}
+	for rows.Next() {
+		var passenger model.ReservationPassenger
+		err := rows.Scan(&passenger.PassengerID, &passenger.FirstName, &passenger.LastName, &passenger.PassengerType,
+			&passenger.SeatNumber, &passenger.FareClass, &passenger.FareBasis, &passenger.Price)
+		if err != nil {
+			return nil, err
+		}
+		passengers = append(passengers, passenger)
+	}
+
+	return passengers, rows.Err()
+}
+
+// GetAllReservations retrieves a page of reservations matching the filter together with the total match count.
+// Pages are keyed on reservation_id, the cursor being the last ID of the previous page.
+func (r *FlightRepository) GetAllReservations(filter model.ReservationFilter) (reservations []model.Reservation, total int, err error) {
+	where := []string{"1 = 1"}
+	args := []interface{}{}
+
+	if filter.FlightNumber != "" {
+		where = append(where, "flight_number = ?")
+		args = append(args, filter.FlightNumber)
+	}
+	if filter.PassengerID > 0 {
+		where = append(where, "EXISTS (SELECT 1 FROM reservation_passengers rp WHERE rp.reservation_id = reservations.reservation_id AND rp.passenger_id = ?)")
+		args = append(args, filter.PassengerID)
+	}
+	if filter.Status != "" {
+		where = append(where, "status = ?")
+		args = append(args, filter.Status)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		where = append(where, "created_at >= ?")
+		args = append(args, filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		where = append(where, "created_at < ?")
+		args = append(args, filter.CreatedTo)
+	}
+
+	if err := r.DB.QueryRow("SELECT COUNT(*) FROM reservations WHERE "+strings.Join(where, " AND "), args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	direction := "ASC"
+	if filter.SortDesc {
+		direction = "DESC"
+	}
+	if filter.AfterID > 0 {
+		if filter.SortDesc {
+			where = append(where, "reservation_id < ?")
+		} else {
+			where = append(where, "reservation_id > ?")
+		}
+		args = append(args, filter.AfterID)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM reservations WHERE %s ORDER BY reservation_id %s LIMIT ?",
+		reservationColumns, strings.Join(where, " AND "), direction)
+	rows, err := r.DB.Query(query, append(args, filter.Limit)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	reservations = []model.Reservation{}
+	ids := []interface{}{}
+	for rows.Next() {
+		reservation, err := scanReservation(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		reservations = append(reservations, reservation)
+		ids = append(ids, reservation.ReservationID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	if len(reservations) == 0 {
+		return reservations, total, nil
+	}
+
+	passengers, err := r.getPassengersOfReservations(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range reservations {
+		reservations[i].Passengers = passengers[reservations[i].ReservationID]
+	}
+
+	return reservations, total, nil
+}
+
+// getPassengersOfReservations loads the passengers of several reservations in one query
+func (r *FlightRepository) getPassengersOfReservations(ids []interface{}) (map[int][]model.ReservationPassenger, error) {
+	query := `SELECT reservation_id, passenger_id, first_name, last_name, passenger_type, seat_number, fare_class, fare_basis, price
+		FROM reservation_passengers WHERE reservation_id IN (?` + strings.Repeat(", ?", len(ids)-1) + `)
+		ORDER BY reservation_passenger_id`
+	rows, err := r.DB.Query(query, ids...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var bookings []model.Reservation
+	passengers := map[int][]model.ReservationPassenger{}
 	for rows.Next() {
-		var booking model.Reservation
-		err := rows.Scan(&booking.ReservationID, &booking.FlightNumber, &booking.PassengerID, &booking.SeatNumber, &booking.Price, &booking.CreatedAt)
+		var (
+			reservationID int
+			passenger     model.ReservationPassenger
+		)
+		err := rows.Scan(&reservationID, &passenger.PassengerID, &passenger.FirstName, &passenger.LastName,
+			&passenger.PassengerType, &passenger.SeatNumber, &passenger.FareClass, &passenger.FareBasis, &passenger.Price)
 		if err != nil {
 			return nil, err
 		}
-		bookings = append(bookings, booking)
+		passengers[reservationID] = append(passengers[reservationID], passenger)
 	}
 
-	return bookings, nil
+	return passengers, rows.Err()
 }
 
-// UpdateFlight updates a flight in the database
+// UpdateInstanceID records the process instance of a reservation. Cancelled or expired reservations are
+// left untouched and reported as a status conflict, their instance has to be stopped instead.
 func (r *FlightRepository) UpdateInstanceID(reservationID int, instanceKey int64) error {
-	_, err := r.DB.Exec("UPDATE reservations SET instance_key=? WHERE reservation_id=?",
-		instanceKey, reservationID)
+	result, err := r.DB.Exec("UPDATE reservations SET instance_key=? WHERE reservation_id=? AND status NOT IN (?, ?)",
+		instanceKey, reservationID, model.ReservationCancelled, model.ReservationExpired)
+	if err != nil {
+		return err
+	}
+
+	return expectRowAffected(result, model.ErrStatusConflict)
+}
+
+// statusTimestampColumns maps each reservation status to the column recording when it was entered
+var statusTimestampColumns = map[string]string{
+	model.ReservationPaid:      "paid_at",
+	model.ReservationTicketed:  "ticketed_at",
+	model.ReservationCancelled: "cancelled_at",
+	model.ReservationExpired:   "expired_at",
+}
+
+// UpdateReservationStatus moves a reservation from one status to another, stamping the time of the transition.
+// The update only applies while the reservation is still in the from status.
+func (r *FlightRepository) UpdateReservationStatus(reservationID int, from string, to string, at time.Time) error {
+	return updateReservationStatus(r.DB, reservationID, from, to, at)
+}
+
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+func updateReservationStatus(db execer, reservationID int, from string, to string, at time.Time) error {
+	column, ok := statusTimestampColumns[to]
+	if !ok {
+		return model.ErrUnknownStatus
+	}
+
+	query := fmt.Sprintf("UPDATE reservations SET status = ?, %s = ? WHERE reservation_id = ? AND status = ?", column)
+	result, err := db.Exec(query, to, at, reservationID, from)
+	if err != nil {
+		return err
+	}
+
+	return expectRowAffected(result, model.ErrStatusConflict)
+}
+
+// CloseReservation cancels or expires a reservation, recording its refund and returning its seats
+// to the flight inventory in a single transaction. It returns the process instance key recorded when the
+// reservation closed, an instance recorded later is refused by UpdateInstanceID.
+func (r *FlightRepository) CloseReservation(reservationID int, from string, to string, refundAmount float64, at time.Time) (instanceKey int64, err error) {
+	tx, err := r.DB.Begin()
 	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if err = updateReservationStatus(tx, reservationID, from, to, at); err != nil {
+		return 0, err
+	}
+	if _, err = tx.Exec("UPDATE reservations SET refund_amount = ? WHERE reservation_id = ?", refundAmount, reservationID); err != nil {
+		return 0, err
+	}
+
+	var flightNumber string
+	err = tx.QueryRow("SELECT flight_number, COALESCE(instance_key, 0) FROM reservations WHERE reservation_id = ?",
+		reservationID).Scan(&flightNumber, &instanceKey)
+	if err != nil {
+		return 0, err
+	}
+	result, err := tx.Exec("DELETE FROM flight_seats WHERE reservation_id = ?", reservationID)
+	if err != nil {
+		return 0, err
+	}
+	released, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	_, err = tx.Exec("UPDATE flights SET available_seats = available_seats + ? WHERE flight_number = ?", released, flightNumber)
+	if err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return instanceKey, nil
+}
+
+// GetExpirableReservations retrieves pending, unpaid reservations created before the cutoff, oldest first
+func (r *FlightRepository) GetExpirableReservations(createdBefore time.Time, limit int) ([]model.Reservation, error) {
+	query := "SELECT " + reservationColumns + ` FROM reservations
+		WHERE status = ? AND payment_status = ? AND created_at < ? ORDER BY reservation_id LIMIT ?`
+	rows, err := r.DB.Query(query, model.ReservationPending, model.PaymentUnpaid, createdBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reservations := []model.Reservation{}
+	for rows.Next() {
+		reservation, err := scanReservation(rows)
+		if err != nil {
+			return nil, err
+		}
+		reservations = append(reservations, reservation)
+	}
+
+	return reservations, rows.Err()
+}
+
+// MarkReservationPaid moves a pending reservation to paid and records the transaction ID of the gateway
+func (r *FlightRepository) MarkReservationPaid(reservationID int, transactionID string, at time.Time) (err error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if err = updateReservationStatus(tx, reservationID, model.ReservationPending, model.ReservationPaid, at); err != nil {
 		return err
 	}
+	_, err = tx.Exec("UPDATE reservations SET payment_status = ?, transaction_id = ? WHERE reservation_id = ?",
+		model.PaymentPaid, transactionID, reservationID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdatePaymentReference records the charge a reservation is being paid through
+func (r *FlightRepository) UpdatePaymentReference(reservationID int, paymentReference string) error {
+	_, err := r.DB.Exec("UPDATE reservations SET payment_reference=? WHERE reservation_id=?",
+		paymentReference, reservationID)
+	return err
+}
 
-	return nil
+// UpdatePaymentStatus sets the payment status of a reservation
+func (r *FlightRepository) UpdatePaymentStatus(reservationID int, paymentStatus string) error {
+	_, err := r.DB.Exec("UPDATE reservations SET payment_status=? WHERE reservation_id=?",
+		paymentStatus, reservationID)
+	return err
 }
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"booking-engine/internal/model"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+const maxOutboxErrorLength = 512
+
+// insertWorkflowOutbox records the process instance to start for a reservation, within the transaction saving it.
+// The first attempt is due at once, written from Go like the time the dispatcher compares it with.
+func insertWorkflowOutbox(db execer, reservationID int, process model.ProcessDefinition, variables interface{}, at time.Time) error {
+	raw, err := json.Marshal(variables)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`INSERT INTO workflow_outbox (reservation_id, process_id, process_version, variables, next_attempt_at)
+		VALUES (?, ?, ?, ?, ?)`, reservationID, process.ProcessID, process.Version, raw, at)
+	return err
+}
+
+// GetPendingOutboxEntries retrieves the outbox entries due for an attempt, oldest first
+func (r *FlightRepository) GetPendingOutboxEntries(now time.Time, limit int) ([]model.WorkflowOutboxEntry, error) {
+	query := `SELECT outbox_id, reservation_id, process_id, process_version, variables, attempts, next_attempt_at, last_error
+		FROM workflow_outbox WHERE dispatched_at IS NULL AND next_attempt_at <= ? ORDER BY outbox_id LIMIT ?`
+	rows, err := r.DB.Query(query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []model.WorkflowOutboxEntry{}
+	for rows.Next() {
+		var entry model.WorkflowOutboxEntry
+		var variables []byte
+		err := rows.Scan(&entry.OutboxID, &entry.ReservationID, &entry.ProcessID, &entry.ProcessVersion, &variables, &entry.Attempts,
+			&entry.NextAttemptAt, &entry.LastError)
+		if err != nil {
+			return nil, err
+		}
+		entry.Variables = variables
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// GetOutboxEntry retrieves the latest outbox entry of a reservation, whether dispatched or not
+func (r *FlightRepository) GetOutboxEntry(reservationID int) (model.WorkflowOutboxEntry, error) {
+	query := `SELECT outbox_id, reservation_id, process_id, process_version, variables, attempts, next_attempt_at, last_error
+		FROM workflow_outbox WHERE reservation_id = ? ORDER BY outbox_id DESC LIMIT 1`
+
+	var entry model.WorkflowOutboxEntry
+	var variables []byte
+	err := r.DB.QueryRow(query, reservationID).Scan(&entry.OutboxID, &entry.ReservationID, &entry.ProcessID,
+		&entry.ProcessVersion, &variables, &entry.Attempts, &entry.NextAttemptAt, &entry.LastError)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = model.ErrWorkflowInstanceNotFound
+		}
+		return entry, err
+	}
+	entry.Variables = variables
+
+	return entry, nil
+}
+
+// ClaimOutboxEntry counts an attempt on an entry and pushes its next attempt back to retryAt. The update only applies
+// when nobody else counted an attempt since the entry was read, so concurrent dispatchers never start it twice.
+func (r *FlightRepository) ClaimOutboxEntry(entry model.WorkflowOutboxEntry, retryAt time.Time) (bool, error) {
+	result, err := r.DB.Exec(`UPDATE workflow_outbox SET attempts = attempts + 1, next_attempt_at = ?
+		WHERE outbox_id = ? AND attempts = ? AND dispatched_at IS NULL`,
+		retryAt, entry.OutboxID, entry.Attempts)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// RecordOutboxError keeps the error of the latest failed attempt on an entry
+func (r *FlightRepository) RecordOutboxError(outboxID int, message string) error {
+	if len(message) > maxOutboxErrorLength {
+		message = message[:maxOutboxErrorLength]
+	}
+
+	_, err := r.DB.Exec("UPDATE workflow_outbox SET last_error = ? WHERE outbox_id = ?", message, outboxID)
+	return err
+}
+
+// MarkOutboxDispatched marks an entry done so it is never attempted again
+func (r *FlightRepository) MarkOutboxDispatched(outboxID int, at time.Time) error {
+	_, err := r.DB.Exec("UPDATE workflow_outbox SET dispatched_at = ? WHERE outbox_id = ?", at, outboxID)
+	return err
+}
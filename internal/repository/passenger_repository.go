@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"booking-engine/internal/model"
+	"database/sql"
+)
+
+type PassengerRepository struct {
+	DB *sql.DB
+}
+
+type PassengerPersister interface {
+	CreatePassenger(passenger model.Passenger) (passengerID int, err error)
+	GetPassengerByID(passengerID int) (model.Passenger, error)
+	UpdatePassenger(passenger model.Passenger) error
+	DeletePassenger(passengerID int) error
+}
+
+// NewPassengerRepository creates a new instance of PassengerRepository
+func NewPassengerRepository(passenger PassengerRepository) PassengerPersister {
+	return &passenger
+}
+
+// CreatePassenger saves a new passenger profile to the MySQL database
+func (r *PassengerRepository) CreatePassenger(passenger model.Passenger) (passengerID int, err error) {
+	query := `INSERT INTO passengers
+		(first_name, last_name, date_of_birth, gender, email, phone, nationality, document_type, document_number)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := r.DB.Exec(query, passenger.FirstName, passenger.LastName, passenger.DateOfBirth, passenger.Gender,
+		passenger.Email, passenger.Phone, passenger.Nationality, passenger.DocumentType, passenger.DocumentNumber)
+	if err != nil {
+		return 0, err
+	}
+	lastInsertID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return int(lastInsertID), nil
+}
+
+// GetPassengerByID retrieves a passenger profile by ID from the MySQL database
+func (r *PassengerRepository) GetPassengerByID(passengerID int) (model.Passenger, error) {
+	query := `SELECT passenger_id, first_name, last_name, DATE_FORMAT(date_of_birth, '%Y-%m-%d'), gender, email, phone,
+		nationality, document_type, document_number, created_at, updated_at
+		FROM passengers WHERE passenger_id = ?`
+	row := r.DB.QueryRow(query, passengerID)
+
+	var passenger model.Passenger
+	err := row.Scan(&passenger.PassengerID, &passenger.FirstName, &passenger.LastName, &passenger.DateOfBirth,
+		&passenger.Gender, &passenger.Email, &passenger.Phone, &passenger.Nationality, &passenger.DocumentType,
+		&passenger.DocumentNumber, &passenger.CreatedAt, &passenger.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return passenger, model.ErrPassengerNotFound
+		}
+		return passenger, err
+	}
+
+	return passenger, nil
+}
+
+// UpdatePassenger updates a passenger profile in the MySQL database
+func (r *PassengerRepository) UpdatePassenger(passenger model.Passenger) error {
+	query := `UPDATE passengers SET first_name = ?, last_name = ?, date_of_birth = ?, gender = ?, email = ?, phone = ?,
+		nationality = ?, document_type = ?, document_number = ? WHERE passenger_id = ?`
+	_, err := r.DB.Exec(query, passenger.FirstName, passenger.LastName, passenger.DateOfBirth, passenger.Gender,
+		passenger.Email, passenger.Phone, passenger.Nationality, passenger.DocumentType, passenger.DocumentNumber,
+		passenger.PassengerID)
+	return err
+}
+
+// DeletePassenger deletes a passenger profile that is not referenced by any reservation
+func (r *PassengerRepository) DeletePassenger(passengerID int) error {
+	var reservations int
+	err := r.DB.QueryRow("SELECT COUNT(*) FROM reservation_passengers WHERE passenger_id = ?", passengerID).Scan(&reservations)
+	if err != nil {
+		return err
+	}
+	if reservations > 0 {
+		return model.ErrPassengerInUse
+	}
+
+	result, err := r.DB.Exec("DELETE FROM passengers WHERE passenger_id = ?", passengerID)
+	if err != nil {
+		return err
+	}
+
+	return expectRowAffected(result, model.ErrPassengerNotFound)
+}
+
+// expectRowAffected returns notFound when the statement did not match any row
+func expectRowAffected(result sql.Result, notFound error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return notFound
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"booking-engine/internal/model"
+	"database/sql"
+	"encoding/json"
+	"sort"
+)
+
+// ModifyReservation applies a seat or flight change to a reservation while it is still in the given status.
+// Seats are swapped and inventory moved between flights in one transaction, and the change is appended to the history.
+func (r *FlightRepository) ModifyReservation(status string, change model.ReservationChange) (changeID int, err error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var current string
+	err = tx.QueryRow("SELECT status FROM reservations WHERE reservation_id = ? FOR UPDATE", change.ReservationID).Scan(&current)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = model.ErrReservationNotFound
+		}
+		return 0, err
+	}
+	if current != status {
+		err = model.ErrStatusConflict
+		return 0, err
+	}
+
+	seated := 0
+	for _, passenger := range change.Passengers {
+		if passenger.NewSeat != "" {
+			seated++
+		}
+	}
+
+	if change.NewFlightNumber != change.OldFlightNumber {
+		if err = moveInventory(tx, change.OldFlightNumber, change.NewFlightNumber, seated); err != nil {
+			return 0, err
+		}
+	}
+
+	// Releasing every seat first lets passengers of the same reservation swap seats with each other
+	if _, err = tx.Exec("DELETE FROM flight_seats WHERE reservation_id = ?", change.ReservationID); err != nil {
+		return 0, err
+	}
+	for _, passenger := range change.Passengers {
+		_, err = tx.Exec(`UPDATE reservation_passengers SET seat_number = ?, fare_class = ?, fare_basis = ?, price = ?
+			WHERE reservation_id = ? AND passenger_id = ?`,
+			passenger.NewSeat, passenger.NewFareClass, passenger.FareBasis, passenger.NewPrice, change.ReservationID, passenger.PassengerID)
+		if err != nil {
+			return 0, err
+		}
+		if passenger.NewSeat == "" {
+			continue
+		}
+		_, err = tx.Exec("INSERT INTO flight_seats (flight_number, seat_number, reservation_id) VALUES (?, ?, ?)",
+			change.NewFlightNumber, passenger.NewSeat, change.ReservationID)
+		if err != nil {
+			if isDuplicateKey(err) {
+				err = model.ErrSeatTaken
+			}
+			return 0, err
+		}
+	}
+
+	lead := change.Passengers[0]
+	_, err = tx.Exec("UPDATE reservations SET flight_number = ?, seat_number = ?, price = price + ? WHERE reservation_id = ?",
+		change.NewFlightNumber, lead.NewSeat, change.FareDifference+change.ChangeFee, change.ReservationID)
+	if err != nil {
+		return 0, err
+	}
+
+	passengers, err := json.Marshal(change.Passengers)
+	if err != nil {
+		return 0, err
+	}
+	// Changes that cost nothing have no charge, the unique key only stops one charge paying for two changes
+	var chargeID sql.NullString
+	if change.ChargeID != "" {
+		chargeID = sql.NullString{String: change.ChargeID, Valid: true}
+	}
+	result, err := tx.Exec(`INSERT INTO reservation_changes
+		(reservation_id, old_flight_number, new_flight_number, passengers, fare_difference, change_fee, charge_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		change.ReservationID, change.OldFlightNumber, change.NewFlightNumber, passengers, change.FareDifference,
+		change.ChangeFee, chargeID, change.CreatedAt)
+	if err != nil {
+		if isDuplicateKey(err) {
+			err = model.ErrChargeAlreadyUsed
+		}
+		return 0, err
+	}
+	lastInsertID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+	return int(lastInsertID), nil
+}
+
+// GetReservationChanges retrieves the change history of a reservation, oldest first
+func (r *FlightRepository) GetReservationChanges(reservationID int) ([]model.ReservationChange, error) {
+	query := `SELECT change_id, reservation_id, old_flight_number, new_flight_number, passengers, fare_difference, change_fee,
+		COALESCE(charge_id, ''), created_at FROM reservation_changes WHERE reservation_id = ? ORDER BY change_id`
+	rows, err := r.DB.Query(query, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []model.ReservationChange{}
+	for rows.Next() {
+		var (
+			change     model.ReservationChange
+			passengers []byte
+		)
+		err := rows.Scan(&change.ChangeID, &change.ReservationID, &change.OldFlightNumber, &change.NewFlightNumber,
+			&passengers, &change.FareDifference, &change.ChangeFee, &change.ChargeID, &change.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(passengers, &change.Passengers); err != nil {
+			return nil, err
+		}
+		change.AmountDue = change.FareDifference + change.ChangeFee
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
+
+// moveInventory returns seats to one flight and takes them from another, locking both flight rows
+// in a fixed order so two opposite moves cannot deadlock
+func moveInventory(tx *sql.Tx, from string, to string, seats int) error {
+	flights := []string{from, to}
+	sort.Strings(flights)
+
+	available := map[string]int{}
+	for _, flightNumber := range flights {
+		var seatsLeft int
+		err := tx.QueryRow("SELECT available_seats FROM flights WHERE flight_number = ? FOR UPDATE", flightNumber).Scan(&seatsLeft)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return model.ErrFlightNotFound
+			}
+			return err
+		}
+		available[flightNumber] = seatsLeft
+	}
+	if available[to] < seats {
+		return model.ErrFlightSoldOut
+	}
+
+	if _, err := tx.Exec("UPDATE flights SET available_seats = available_seats + ? WHERE flight_number = ?", seats, from); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE flights SET available_seats = available_seats - ? WHERE flight_number = ?", seats, to); err != nil {
+		return err
+	}
+
+	return nil
+}
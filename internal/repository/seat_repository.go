@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"booking-engine/internal/model"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const mysqlErrDuplicateEntry = 1062
+
+// GetSeatLayout retrieves the seat layout of an aircraft type from the MySQL database
+func (r *FlightRepository) GetSeatLayout(aircraftType string) (model.SeatLayout, error) {
+	query := "SELECT aircraft_type, seat_rows, seat_columns, cabins, exit_rows, blocked_seats FROM seat_layouts WHERE aircraft_type = ?"
+	row := r.DB.QueryRow(query, aircraftType)
+
+	var (
+		layout                         model.SeatLayout
+		cabins, exitRows, blockedSeats []byte
+	)
+	err := row.Scan(&layout.AircraftType, &layout.Rows, &layout.Columns, &cabins, &exitRows, &blockedSeats)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return layout, model.ErrSeatLayoutNotFound
+		}
+		return layout, err
+	}
+
+	if err := json.Unmarshal(cabins, &layout.Cabins); err != nil {
+		return layout, err
+	}
+	if err := json.Unmarshal(exitRows, &layout.ExitRows); err != nil {
+		return layout, err
+	}
+	if err := json.Unmarshal(blockedSeats, &layout.BlockedSeats); err != nil {
+		return layout, err
+	}
+
+	return layout, nil
+}
+
+// UpsertSeatLayout inserts or replaces the seat layout of an aircraft type
+func (r *FlightRepository) UpsertSeatLayout(layout model.SeatLayout) error {
+	cabins, err := json.Marshal(nonNil(layout.Cabins))
+	if err != nil {
+		return err
+	}
+	exitRows, err := json.Marshal(nonNil(layout.ExitRows))
+	if err != nil {
+		return err
+	}
+	blockedSeats, err := json.Marshal(nonNil(layout.BlockedSeats))
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO seat_layouts (aircraft_type, seat_rows, seat_columns, cabins, exit_rows, blocked_seats)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE seat_rows = VALUES(seat_rows), seat_columns = VALUES(seat_columns),
+			cabins = VALUES(cabins), exit_rows = VALUES(exit_rows), blocked_seats = VALUES(blocked_seats)`
+	_, err = r.DB.Exec(query, layout.AircraftType, layout.Rows, layout.Columns, cabins, exitRows, blockedSeats)
+	return err
+}
+
+// GetOccupiedSeats retrieves the seat numbers already sold on a flight
+func (r *FlightRepository) GetOccupiedSeats(flightNumber string) ([]string, error) {
+	rows, err := r.DB.Query("SELECT seat_number FROM flight_seats WHERE flight_number = ?", flightNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	seats := []string{}
+	for rows.Next() {
+		var seat string
+		if err := rows.Scan(&seat); err != nil {
+			return nil, err
+		}
+		seats = append(seats, seat)
+	}
+
+	return seats, rows.Err()
+}
+
+func isDuplicateKey(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry
+}
+
+// nonNil makes sure empty slices are stored as JSON arrays rather than null
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
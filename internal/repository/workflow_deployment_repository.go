@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"booking-engine/internal/model"
+)
+
+// GetWorkflowDeployments retrieves the latest recorded deployment of each process of a workflow resource
+func (r *FlightRepository) GetWorkflowDeployments(resourceName string) ([]model.WorkflowDeployment, error) {
+	query := `SELECT resource_name, process_id, checksum, version, process_key, deployed_at
+		FROM workflow_deployments d WHERE resource_name = ? AND deployment_id = (
+			SELECT MAX(deployment_id) FROM workflow_deployments
+			WHERE resource_name = d.resource_name AND process_id = d.process_id)
+		ORDER BY process_id`
+	rows, err := r.DB.Query(query, resourceName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deployments := []model.WorkflowDeployment{}
+	for rows.Next() {
+		var deployment model.WorkflowDeployment
+		err := rows.Scan(&deployment.ResourceName, &deployment.ProcessID, &deployment.Checksum, &deployment.Version,
+			&deployment.ProcessKey, &deployment.DeployedAt)
+		if err != nil {
+			return nil, err
+		}
+		deployments = append(deployments, deployment)
+	}
+
+	return deployments, rows.Err()
+}
+
+// RecordWorkflowDeployments appends deployments to the deployment history
+func (r *FlightRepository) RecordWorkflowDeployments(deployments []model.WorkflowDeployment) error {
+	for _, deployment := range deployments {
+		_, err := r.DB.Exec(`INSERT INTO workflow_deployments
+			(resource_name, process_id, checksum, version, process_key, deployed_at) VALUES (?, ?, ?, ?, ?, ?)`,
+			deployment.ResourceName, deployment.ProcessID, deployment.Checksum, deployment.Version, deployment.ProcessKey,
+			deployment.DeployedAt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"strings"
+	"time"
+)
+
+const MaxPassengersPerBooking = 9
+
+// normalizeBookingRequest folds the single passenger shorthand into Passengers and validates the passenger list
+func normalizeBookingRequest(bookingRequest model.BookingRequest) (model.BookingRequest, error) {
+	bookingRequest.FlightNumber = strings.ToUpper(strings.TrimSpace(bookingRequest.FlightNumber))
+
+	if len(bookingRequest.Passengers) == 0 {
+		bookingRequest.Passengers = []model.BookingPassenger{{
+			PassengerID:   bookingRequest.PassengerID,
+			PassengerType: bookingRequest.PassengerType,
+			SeatNumber:    bookingRequest.SeatNumber,
+			FareClass:     bookingRequest.FareClass,
+			Price:         bookingRequest.Price,
+		}}
+		bookingRequest.Price = 0
+	} else if bookingRequest.PassengerID != 0 || bookingRequest.SeatNumber != "" {
+		return bookingRequest, model.ErrInvalidBooking
+	}
+	bookingRequest.PassengerID = 0
+	bookingRequest.PassengerType = ""
+	bookingRequest.SeatNumber = ""
+	bookingRequest.FareClass = ""
+
+	if bookingRequest.FlightNumber == "" || len(bookingRequest.Passengers) > MaxPassengersPerBooking {
+		return bookingRequest, model.ErrInvalidBooking
+	}
+
+	seats := map[string]bool{}
+	passengers := map[int]bool{}
+	for i := range bookingRequest.Passengers {
+		passenger := &bookingRequest.Passengers[i]
+		// The passenger type is derived from the profile later on, a type given here only has to agree with it
+		passenger.PassengerType = strings.ToLower(strings.TrimSpace(passenger.PassengerType))
+		passenger.SeatNumber = normalizeSeatNumber(passenger.SeatNumber)
+
+		if passenger.PassengerID <= 0 || passengers[passenger.PassengerID] {
+			return bookingRequest, model.ErrInvalidBooking
+		}
+		passengers[passenger.PassengerID] = true
+
+		if passenger.SeatNumber == "" {
+			continue
+		}
+		if seats[passenger.SeatNumber] {
+			return bookingRequest, model.ErrInvalidBooking
+		}
+		seats[passenger.SeatNumber] = true
+	}
+
+	return bookingRequest, nil
+}
+
+// checkPassengerMix validates the passenger list once every passenger type is known
+func checkPassengerMix(passengers []model.BookingPassenger) error {
+	adults, infants := 0, 0
+	for _, passenger := range passengers {
+		switch passenger.PassengerType {
+		case model.PassengerAdult:
+			adults++
+		case model.PassengerInfant:
+			infants++
+		}
+
+		// Only infants may travel without a seat of their own
+		if passenger.SeatNumber == "" && passenger.PassengerType != model.PassengerInfant {
+			return model.ErrInvalidBooking
+		}
+	}
+
+	// Every infant has to be accompanied by an adult and the lead passenger cannot be an infant
+	if infants > adults || passengers[0].PassengerType == model.PassengerInfant {
+		return model.ErrInvalidBooking
+	}
+	return nil
+}
+
+// passengerTypeAt returns the passenger type for someone born on dateOfBirth travelling at departure.
+// Infants are under 2 and children under 12 on the day of departure.
+func passengerTypeAt(dateOfBirth string, departure time.Time) (string, error) {
+	// Profiles read back from the database carry a full timestamp, only the date part matters
+	if len(dateOfBirth) > len(searchDateLayout) {
+		dateOfBirth = dateOfBirth[:len(searchDateLayout)]
+	}
+	born, err := time.Parse(searchDateLayout, dateOfBirth)
+	if err != nil || born.After(departure) {
+		return "", model.ErrInvalidPassengerType
+	}
+
+	age := departure.Year() - born.Year()
+	if departure.Month() < born.Month() || (departure.Month() == born.Month() && departure.Day() < born.Day()) {
+		age--
+	}
+
+	switch {
+	case age < 2:
+		return model.PassengerInfant, nil
+	case age < 12:
+		return model.PassengerChild, nil
+	default:
+		return model.PassengerAdult, nil
+	}
+}
+
+// reservationPassengers converts the priced booking passengers to their reservation form
+func reservationPassengers(passengers []model.BookingPassenger) []model.ReservationPassenger {
+	result := make([]model.ReservationPassenger, 0, len(passengers))
+	for _, passenger := range passengers {
+		result = append(result, model.ReservationPassenger{
+			PassengerID:   passenger.PassengerID,
+			FirstName:     passenger.FirstName,
+			LastName:      passenger.LastName,
+			PassengerType: passenger.PassengerType,
+			SeatNumber:    passenger.SeatNumber,
+			FareClass:     passenger.FareClass,
+			FareBasis:     passenger.FareClass,
+			Price:         passenger.Price,
+		})
+	}
+	return result
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	lookupWindow             = 15 * time.Minute
+	lookupMaxAttemptsPerIP   = 10
+	lookupMaxAttemptsPerPNR  = 5 // per client and locator
+	lookupAttemptsKeyPattern = "booking-lookup:%s:%s"
+)
+
+// LookupBooking returns a reservation and its flight when the locator and one passenger's last name match.
+// Attempts are counted per client, and per client and locator, so locators cannot be enumerated.
+// They are never counted per locator alone, which would let anyone lock a passenger out of their booking.
+func (s *FlightUsecase) LookupBooking(request model.BookingLookupRequest, clientIP string) (model.BookingDetail, error) {
+	locator := strings.ToUpper(strings.TrimSpace(request.Locator))
+	lastName := strings.TrimSpace(request.LastName)
+	if len(locator) != locatorLength || lastName == "" {
+		return model.BookingDetail{}, model.ErrInvalidLookup
+	}
+
+	// Attempts are counted before the lookup, so concurrent guesses cannot all pass a check made ahead of the count
+	ctx := context.Background()
+	locatorKey := fmt.Sprintf(lookupAttemptsKeyPattern, "ip-locator", clientIP+":"+locator)
+	if err := s.countLookupAttempt(ctx, fmt.Sprintf(lookupAttemptsKeyPattern, "ip", clientIP), lookupMaxAttemptsPerIP); err != nil {
+		return model.BookingDetail{}, err
+	}
+	if err := s.countLookupAttempt(ctx, locatorKey, lookupMaxAttemptsPerPNR); err != nil {
+		return model.BookingDetail{}, err
+	}
+
+	reservation, err := s.FlightRepo.GetReservationByLocator(locator)
+	if err != nil && err != model.ErrReservationNotFound {
+		return model.BookingDetail{}, err
+	}
+	// An unknown locator and a wrong last name look the same to the caller
+	if err == model.ErrReservationNotFound || !hasPassengerNamed(reservation, lastName) {
+		return model.BookingDetail{}, model.ErrReservationNotFound
+	}
+
+	flight, err := s.FlightRepo.GetFlightByNumber(reservation.FlightNumber)
+	if err != nil {
+		return model.BookingDetail{}, err
+	}
+
+	// A passenger who found their booking starts afresh on it
+	if err := s.Cacher.Del(ctx, locatorKey); err != nil {
+		return model.BookingDetail{}, err
+	}
+
+	return model.BookingDetail{
+		Reservation: reservation,
+		Flight:      flight,
+	}, nil
+}
+
+// countLookupAttempt records an attempt and rejects it once the attempts in the window exceed max
+func (s *FlightUsecase) countLookupAttempt(ctx context.Context, key string, max int) error {
+	attempts, err := s.Cacher.Incr(ctx, key, lookupWindow)
+	if err != nil {
+		return err
+	}
+	if attempts > int64(max) {
+		return model.ErrTooManyAttempts
+	}
+
+	return nil
+}
+
+func hasPassengerNamed(reservation model.Reservation, lastName string) bool {
+	for _, passenger := range reservation.Passengers {
+		if strings.EqualFold(passenger.LastName, lastName) {
+			return true
+		}
+	}
+	return false
+}
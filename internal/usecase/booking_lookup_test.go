@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"testing"
+)
+
+func newLookupUsecase() (*FlightUsecase, *memoryCacher) {
+	repo := newMemoryFlightRepository(
+		model.Flight{FlightID: 1, FlightNumber: "GA402", AircraftType: "ATR72"},
+		model.SeatLayout{AircraftType: "ATR72", Rows: 3, Columns: "AB"},
+	)
+	repo.reservations[1] = model.Reservation{
+		ReservationID: 1,
+		Locator:       "ABC234",
+		FlightNumber:  "GA402",
+		Passengers:    []model.ReservationPassenger{{PassengerID: 1, LastName: "Wijaya"}},
+	}
+	cacher := newMemoryCacher()
+	return &FlightUsecase{FlightRepo: repo, Cacher: cacher}, cacher
+}
+
+func TestLookupBooking(t *testing.T) {
+	found := model.BookingLookupRequest{Locator: " abc234 ", LastName: "wijaya"}
+	wrongName := model.BookingLookupRequest{Locator: "ABC234", LastName: "Santoso"}
+	unknown := model.BookingLookupRequest{Locator: "ZZZ999", LastName: "Wijaya"}
+
+	tests := []struct {
+		name    string
+		before  []model.BookingLookupRequest
+		request model.BookingLookupRequest
+		wantErr error
+	}{
+		{name: "found", request: found},
+		{name: "wrong last name", request: wrongName, wantErr: model.ErrReservationNotFound},
+		{name: "unknown locator", request: unknown, wantErr: model.ErrReservationNotFound},
+		{name: "malformed locator", request: model.BookingLookupRequest{Locator: "ABC", LastName: "Wijaya"}, wantErr: model.ErrInvalidLookup},
+		{name: "no last name", request: model.BookingLookupRequest{Locator: "ABC234"}, wantErr: model.ErrInvalidLookup},
+		{
+			name:    "locator limit reached by the client",
+			before:  repeatLookup(wrongName, lookupMaxAttemptsPerPNR),
+			request: found,
+			wantErr: model.ErrTooManyAttempts,
+		},
+		{
+			name:    "client limit reached across locators",
+			before:  repeatLookup(unknown, lookupMaxAttemptsPerIP),
+			request: found,
+			wantErr: model.ErrTooManyAttempts,
+		},
+		{
+			name:    "found before the locator limit resets it",
+			before:  append(repeatLookup(wrongName, lookupMaxAttemptsPerPNR-1), found),
+			request: wrongName,
+			wantErr: model.ErrReservationNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase, _ := newLookupUsecase()
+			for _, request := range tt.before {
+				usecase.LookupBooking(request, "10.0.0.1")
+			}
+
+			detail, err := usecase.LookupBooking(tt.request, "10.0.0.1")
+			if err != tt.wantErr {
+				t.Fatalf("LookupBooking() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (detail.Reservation.Locator != "ABC234" || detail.Flight.FlightNumber != "GA402") {
+				t.Errorf("LookupBooking() = %+v", detail)
+			}
+		})
+	}
+}
+
+func TestLookupBookingLimitsPerClient(t *testing.T) {
+	usecase, _ := newLookupUsecase()
+	wrongName := model.BookingLookupRequest{Locator: "ABC234", LastName: "Santoso"}
+	for i := 0; i < lookupMaxAttemptsPerPNR; i++ {
+		usecase.LookupBooking(wrongName, "10.0.0.1")
+	}
+
+	// Guesses from one client never lock the passenger out of their own booking
+	if _, err := usecase.LookupBooking(model.BookingLookupRequest{Locator: "ABC234", LastName: "Wijaya"}, "10.0.0.2"); err != nil {
+		t.Errorf("LookupBooking() from another client error = %v", err)
+	}
+}
+
+func repeatLookup(request model.BookingLookupRequest, times int) []model.BookingLookupRequest {
+	requests := make([]model.BookingLookupRequest, times)
+	for i := range requests {
+		requests[i] = request
+	}
+	return requests
+}
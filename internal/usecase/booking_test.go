@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"testing"
+	"time"
+)
+
+func TestNormalizeBookingRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		request model.BookingRequest
+		want    []model.BookingPassenger
+		wantErr error
+	}{
+		{
+			name:    "single passenger shorthand",
+			request: model.BookingRequest{FlightNumber: " ga101 ", PassengerID: 7, PassengerType: "Adult", SeatNumber: "12a"},
+			want:    []model.BookingPassenger{{PassengerID: 7, PassengerType: model.PassengerAdult, SeatNumber: "12A"}},
+		},
+		{
+			name: "passenger list",
+			request: model.BookingRequest{FlightNumber: "GA101", Passengers: []model.BookingPassenger{
+				{PassengerID: 1, SeatNumber: "1a"},
+				{PassengerID: 2, PassengerType: " INFANT "},
+			}},
+			want: []model.BookingPassenger{
+				{PassengerID: 1, SeatNumber: "1A"},
+				{PassengerID: 2, PassengerType: model.PassengerInfant},
+			},
+		},
+		{
+			name: "shorthand next to a passenger list",
+			request: model.BookingRequest{FlightNumber: "GA101", PassengerID: 1, Passengers: []model.BookingPassenger{
+				{PassengerID: 2, SeatNumber: "1A"},
+			}},
+			wantErr: model.ErrInvalidBooking,
+		},
+		{
+			name:    "missing flight number",
+			request: model.BookingRequest{PassengerID: 1, SeatNumber: "1A"},
+			wantErr: model.ErrInvalidBooking,
+		},
+		{
+			name:    "missing passenger",
+			request: model.BookingRequest{FlightNumber: "GA101", SeatNumber: "1A"},
+			wantErr: model.ErrInvalidBooking,
+		},
+		{
+			name: "same passenger twice",
+			request: model.BookingRequest{FlightNumber: "GA101", Passengers: []model.BookingPassenger{
+				{PassengerID: 1, SeatNumber: "1A"},
+				{PassengerID: 1, SeatNumber: "1B"},
+			}},
+			wantErr: model.ErrInvalidBooking,
+		},
+		{
+			name: "same seat twice",
+			request: model.BookingRequest{FlightNumber: "GA101", Passengers: []model.BookingPassenger{
+				{PassengerID: 1, SeatNumber: "1A"},
+				{PassengerID: 2, SeatNumber: "1a"},
+			}},
+			wantErr: model.ErrInvalidBooking,
+		},
+		{
+			name:    "too many passengers",
+			request: model.BookingRequest{FlightNumber: "GA101", Passengers: make([]model.BookingPassenger, MaxPassengersPerBooking+1)},
+			wantErr: model.ErrInvalidBooking,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeBookingRequest(tt.request)
+			if err != tt.wantErr {
+				t.Fatalf("normalizeBookingRequest() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.FlightNumber != "GA101" || got.PassengerID != 0 || got.SeatNumber != "" {
+				t.Errorf("normalizeBookingRequest() = %+v, shorthand fields should be folded", got)
+			}
+			if len(got.Passengers) != len(tt.want) {
+				t.Fatalf("normalizeBookingRequest() passengers = %+v, want %+v", got.Passengers, tt.want)
+			}
+			for i, passenger := range got.Passengers {
+				if passenger != tt.want[i] {
+					t.Errorf("passenger %d = %+v, want %+v", i, passenger, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCheckPassengerMix(t *testing.T) {
+	adult := model.BookingPassenger{PassengerType: model.PassengerAdult, SeatNumber: "1A"}
+	child := model.BookingPassenger{PassengerType: model.PassengerChild, SeatNumber: "1B"}
+	infant := model.BookingPassenger{PassengerType: model.PassengerInfant}
+
+	tests := []struct {
+		name       string
+		passengers []model.BookingPassenger
+		wantErr    error
+	}{
+		{name: "adult", passengers: []model.BookingPassenger{adult}},
+		{name: "adult with child and infant", passengers: []model.BookingPassenger{adult, child, infant}},
+		{name: "seated infant", passengers: []model.BookingPassenger{adult, {PassengerType: model.PassengerInfant, SeatNumber: "1C"}}},
+		{name: "child alone", passengers: []model.BookingPassenger{child}},
+		{name: "infant leads", passengers: []model.BookingPassenger{infant, adult}, wantErr: model.ErrInvalidBooking},
+		{name: "more infants than adults", passengers: []model.BookingPassenger{adult, infant, infant}, wantErr: model.ErrInvalidBooking},
+		{name: "infant with a child only", passengers: []model.BookingPassenger{child, infant}, wantErr: model.ErrInvalidBooking},
+		{name: "adult without a seat", passengers: []model.BookingPassenger{{PassengerType: model.PassengerAdult}}, wantErr: model.ErrInvalidBooking},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkPassengerMix(tt.passengers); err != tt.wantErr {
+				t.Errorf("checkPassengerMix() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPassengerTypeAt(t *testing.T) {
+	departure := time.Date(2026, 6, 15, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		dateOfBirth string
+		want        string
+		wantErr     error
+	}{
+		{name: "newborn", dateOfBirth: "2026-06-01", want: model.PassengerInfant},
+		{name: "day before second birthday", dateOfBirth: "2024-06-16", want: model.PassengerInfant},
+		{name: "second birthday", dateOfBirth: "2024-06-15", want: model.PassengerChild},
+		{name: "day before twelfth birthday", dateOfBirth: "2014-06-16", want: model.PassengerChild},
+		{name: "twelfth birthday", dateOfBirth: "2014-06-15", want: model.PassengerAdult},
+		{name: "adult", dateOfBirth: "1980-01-31", want: model.PassengerAdult},
+		{name: "timestamp from the database", dateOfBirth: "2025-01-01T00:00:00Z", want: model.PassengerInfant},
+		{name: "born after departure", dateOfBirth: "2026-07-01", wantErr: model.ErrInvalidPassengerType},
+		{name: "not a date", dateOfBirth: "15/06/2020", wantErr: model.ErrInvalidPassengerType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := passengerTypeAt(tt.dateOfBirth, departure)
+			if err != tt.wantErr {
+				t.Fatalf("passengerTypeAt() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("passengerTypeAt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
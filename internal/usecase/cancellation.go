@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"booking-engine/internal/workflow"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultFareRules returns the cancellation and change rules used when none are configured
+func DefaultFareRules() map[string]model.FareRule {
+	return map[string]model.FareRule{
+		model.CabinEconomy: {
+			Refundable: true,
+			CancellationFees: []model.CancellationFee{
+				{MinHoursBefore: 72, Percent: 25},
+				{MinHoursBefore: 24, Percent: 50},
+				{MinHoursBefore: 0, Percent: 100},
+			},
+			ChangeFee: 150000,
+		},
+		model.CabinBusiness: {
+			Refundable: true,
+			CancellationFees: []model.CancellationFee{
+				{MinHoursBefore: 24, Percent: 10},
+				{MinHoursBefore: 0, Percent: 25},
+			},
+		},
+	}
+}
+
+// ParseFareRules reads fare rules keyed by fare class from JSON. Cancellation fee tiers are sorted from the
+// earliest to the latest, as cancellationFeePercent expects, every fee has to be a share of 0 to 100 percent and
+// change fees cannot be negative.
+func ParseFareRules(raw string) (map[string]model.FareRule, error) {
+	rules := map[string]model.FareRule{}
+	if err := json.Unmarshal([]byte(raw), &rules); err != nil {
+		return nil, err
+	}
+
+	for fareClass, rule := range rules {
+		if rule.ChangeFee < 0 {
+			return nil, fmt.Errorf("%w: negative change fee for %s", model.ErrInvalidFareRule, fareClass)
+		}
+		for _, fee := range rule.CancellationFees {
+			if fee.Percent < 0 || fee.Percent > 100 || fee.MinHoursBefore < 0 {
+				return nil, fmt.Errorf("%w: cancellation fee out of range for %s", model.ErrInvalidFareRule, fareClass)
+			}
+		}
+		rule.CancellationFees = sortedCancellationFees(rule.CancellationFees)
+		rules[fareClass] = rule
+	}
+
+	return rules, nil
+}
+
+// sortedCancellationFees returns a copy of the fee tiers ordered from the earliest to the latest
+func sortedCancellationFees(fees []model.CancellationFee) []model.CancellationFee {
+	sorted := append([]model.CancellationFee(nil), fees...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].MinHoursBefore > sorted[j].MinHoursBefore
+	})
+	return sorted
+}
+
+// CancelReservation cancels a reservation, refunding what its fare rules allow and releasing its seats
+func (s *FlightUsecase) CancelReservation(id int) (model.Reservation, error) {
+	reservation, err := s.FlightRepo.GetBookingByID(id)
+	if err != nil {
+		return model.Reservation{}, err
+	}
+	if err := checkTransition(reservation.Status, model.ReservationCancelled); err != nil {
+		return model.Reservation{}, err
+	}
+
+	flight, err := s.FlightRepo.GetFlightByNumber(reservation.FlightNumber)
+	if err != nil {
+		return model.Reservation{}, err
+	}
+	now := time.Now()
+	if !now.Before(flight.DepartureTime) {
+		return model.Reservation{}, model.ErrFlightDeparted
+	}
+
+	refund := 0.0
+	if reservation.PaymentStatus == model.PaymentPaid {
+		refund = s.refundAmount(reservation, flight.DepartureTime.Sub(now))
+	}
+
+	// The instance key is read as the reservation closes, it may have been recorded since the reservation was loaded
+	instanceKey, err := s.FlightRepo.CloseReservation(id, reservation.Status, model.ReservationCancelled, refund, now)
+	if err != nil {
+		return model.Reservation{}, err
+	}
+	s.stopProcessInstance(instanceKey)
+	if refund > 0 {
+		s.refundPayment(reservation, refund)
+	}
+
+	return s.FlightRepo.GetBookingByID(id)
+}
+
+// refundAmount applies the fare rule of each passenger's fare basis to the price they paid
+func (s *FlightUsecase) refundAmount(reservation model.Reservation, beforeDeparture time.Duration) float64 {
+	rules := s.fareRules()
+
+	refund := 0.0
+	for _, passenger := range reservation.Passengers {
+		rule, ok := rules[fareBasis(passenger)]
+		if !ok || !rule.Refundable {
+			continue
+		}
+		refund += passenger.Price * (100 - cancellationFeePercent(rule, beforeDeparture)) / 100
+	}
+	// The reservation price is what was charged, no refund can exceed it
+	if refund > reservation.Price {
+		refund = reservation.Price
+	}
+
+	return roundRupiah(refund)
+}
+
+func (s *FlightUsecase) fareRules() map[string]model.FareRule {
+	if s.FareRules == nil {
+		return DefaultFareRules()
+	}
+	return s.FareRules
+}
+
+// cancellationFeePercent returns the fee of the first tier the cancellation time qualifies for, tiers are
+// expected from the earliest to the latest. Cancelling later than every tier forfeits the whole fare.
+func cancellationFeePercent(rule model.FareRule, beforeDeparture time.Duration) float64 {
+	for _, fee := range rule.CancellationFees {
+		if beforeDeparture.Hours() >= fee.MinHoursBefore {
+			return fee.Percent
+		}
+	}
+	return 100
+}
+
+// stopProcessInstance cancels the BPMN process instance of a closed reservation so its remaining steps do not run.
+// The reservation is already closed at this point, so failures are logged rather than returned. An instance that
+// already completed cannot be cancelled either and is logged the same way.
+func (s *FlightUsecase) stopProcessInstance(instanceKey int64) {
+	if instanceKey == 0 {
+		return
+	}
+
+	err := s.Workflow.CancelInstance(context.Background(), instanceKey)
+	// A missing instance has already completed, which is fine
+	if err != nil && !errors.Is(err, workflow.ErrInstanceNotFound) {
+		s.Logger.Error("failed to cancel process instance", zap.Int64("instance_key", instanceKey), zap.Error(err))
+	}
+}
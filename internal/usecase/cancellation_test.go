@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCancellationFeePercent(t *testing.T) {
+	rule := DefaultFareRules()[model.CabinEconomy]
+
+	tests := []struct {
+		name            string
+		rule            model.FareRule
+		beforeDeparture time.Duration
+		want            float64
+	}{
+		{name: "a week ahead", rule: rule, beforeDeparture: 7 * 24 * time.Hour, want: 25},
+		{name: "exactly 72 hours ahead", rule: rule, beforeDeparture: 72 * time.Hour, want: 25},
+		{name: "just under 72 hours ahead", rule: rule, beforeDeparture: 72*time.Hour - time.Minute, want: 50},
+		{name: "a day ahead", rule: rule, beforeDeparture: 24 * time.Hour, want: 50},
+		{name: "an hour ahead", rule: rule, beforeDeparture: time.Hour, want: 100},
+		{name: "no tiers", rule: model.FareRule{Refundable: true}, beforeDeparture: 30 * 24 * time.Hour, want: 100},
+		{
+			name:            "later than every tier",
+			rule:            model.FareRule{CancellationFees: []model.CancellationFee{{MinHoursBefore: 48, Percent: 10}}},
+			beforeDeparture: 12 * time.Hour,
+			want:            100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cancellationFeePercent(tt.rule, tt.beforeDeparture); got != tt.want {
+				t.Errorf("cancellationFeePercent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFareRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		wantFees []float64
+		wantErr  error
+	}{
+		{
+			name:     "tiers in any order",
+			raw:      `{"economy":{"refundable":true,"cancellation_fees":[{"min_hours_before":0,"percent":100},{"min_hours_before":72,"percent":25},{"min_hours_before":24,"percent":50}]}}`,
+			wantFees: []float64{72, 24, 0},
+		},
+		{
+			name:    "percent above 100",
+			raw:     `{"economy":{"cancellation_fees":[{"min_hours_before":24,"percent":150}]}}`,
+			wantErr: model.ErrInvalidFareRule,
+		},
+		{
+			name:    "negative percent",
+			raw:     `{"economy":{"cancellation_fees":[{"min_hours_before":24,"percent":-5}]}}`,
+			wantErr: model.ErrInvalidFareRule,
+		},
+		{
+			name:    "negative hours",
+			raw:     `{"economy":{"cancellation_fees":[{"min_hours_before":-1,"percent":50}]}}`,
+			wantErr: model.ErrInvalidFareRule,
+		},
+		{
+			name:    "negative change fee",
+			raw:     `{"economy":{"change_fee":-1}}`,
+			wantErr: model.ErrInvalidFareRule,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := ParseFareRules(tt.raw)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseFareRules() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			fees := rules[model.CabinEconomy].CancellationFees
+			if len(fees) != len(tt.wantFees) {
+				t.Fatalf("ParseFareRules() fees = %+v, want hours %v", fees, tt.wantFees)
+			}
+			for i, fee := range fees {
+				if fee.MinHoursBefore != tt.wantFees[i] {
+					t.Errorf("fee %d = %+v, want %v hours", i, fee, tt.wantFees[i])
+				}
+			}
+		})
+	}
+
+	if _, err := ParseFareRules("not json"); err == nil {
+		t.Error("ParseFareRules() accepted malformed JSON")
+	}
+}
+
+func TestSortedCancellationFees(t *testing.T) {
+	fees := []model.CancellationFee{{MinHoursBefore: 0, Percent: 100}, {MinHoursBefore: 24, Percent: 50}}
+
+	sorted := sortedCancellationFees(fees)
+	if sorted[0].MinHoursBefore != 24 || sorted[1].MinHoursBefore != 0 {
+		t.Errorf("sortedCancellationFees() = %+v, want the 24 hour tier first", sorted)
+	}
+	if fees[0].MinHoursBefore != 0 {
+		t.Errorf("sortedCancellationFees() reordered its argument to %+v", fees)
+	}
+}
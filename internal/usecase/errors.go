@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"errors"
+)
+
+// bookingValidationErrors are the booking errors caused by the request itself
+var bookingValidationErrors = []error{
+	model.ErrInvalidBooking,
+	model.ErrInvalidFareClass,
+	model.ErrInvalidPassengerType,
+	model.ErrSeatNotFound,
+	model.ErrQuoteInvalid,
+}
+
+// bookingDomainErrors are the booking errors a customer can act upon, returned as they are
+var bookingDomainErrors = []error{
+	model.ErrFlightNotFound,
+	model.ErrPassengerNotFound,
+	model.ErrSeatLayoutNotFound,
+	model.ErrPriceMismatch,
+	model.ErrQuoteExpired,
+	model.ErrQuoteMismatch,
+	model.ErrSeatUnavailable,
+	model.ErrSeatTaken,
+	model.ErrSeatHeld,
+	model.ErrFlightSoldOut,
+}
+
+// classifyBookingError sorts a booking failure into a validation failure, a domain error the customer can act upon,
+// or a persistence failure for anything the database or cache returned
+func classifyBookingError(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	for _, target := range bookingValidationErrors {
+		if errors.Is(err, target) {
+			return &model.DomainError{Kind: model.ErrValidationFailed, Op: op, Err: err}
+		}
+	}
+	for _, target := range bookingDomainErrors {
+		if errors.Is(err, target) {
+			return err
+		}
+	}
+	return persistenceFailed(op, err)
+}
+
+func persistenceFailed(op string, err error) error {
+	return &model.DomainError{Kind: model.ErrPersistenceFailed, Op: op, Err: err}
+}
+
+func workflowUnavailable(op string, err error) error {
+	return &model.DomainError{Kind: model.ErrWorkflowUnavailable, Op: op, Err: err}
+}
@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultPaymentWindow = 30 * time.Minute
+	expiryBatchSize      = 100
+)
+
+// ExpireUnpaidReservations expires pending reservations left unpaid longer than the payment window, returning
+// their seats to inventory and stopping their process instances. It returns how many reservations were expired.
+func (s *FlightUsecase) ExpireUnpaidReservations() (int, error) {
+	now := time.Now()
+	reservations, err := s.FlightRepo.GetExpirableReservations(now.Add(-s.paymentWindow()), expiryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, reservation := range reservations {
+		instanceKey, err := s.FlightRepo.CloseReservation(reservation.ReservationID, model.ReservationPending, model.ReservationExpired, 0, now)
+		if err != nil {
+			// Paid or cancelled since it was listed, it is no longer ours to expire
+			if err == model.ErrStatusConflict {
+				continue
+			}
+			return expired, err
+		}
+		expired++
+
+		s.Logger.Info("reservation expired", zap.Int("reservation_id", reservation.ReservationID))
+		s.stopProcessInstance(instanceKey)
+	}
+
+	return expired, nil
+}
+
+func (s *FlightUsecase) paymentWindow() time.Duration {
+	if s.PaymentWindow <= 0 {
+		return DefaultPaymentWindow
+	}
+	return s.PaymentWindow
+}
@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestExpireUnpaidReservations(t *testing.T) {
+	now := time.Now()
+	window := 30 * time.Minute
+
+	repo := newMemoryFlightRepository(model.Flight{FlightNumber: "GA402"}, model.SeatLayout{})
+	add := func(id int, status string, paymentStatus string, age time.Duration) {
+		repo.reservations[id] = model.Reservation{
+			ReservationID: id,
+			FlightNumber:  "GA402",
+			Status:        status,
+			PaymentStatus: paymentStatus,
+			CreatedAt:     now.Add(-age),
+		}
+	}
+	add(1, model.ReservationPending, model.PaymentUnpaid, time.Hour)
+	add(2, model.ReservationPending, model.PaymentUnpaid, 10*time.Minute)
+	add(3, model.ReservationPaid, model.PaymentPaid, time.Hour)
+	add(4, model.ReservationCancelled, model.PaymentUnpaid, time.Hour)
+	add(5, model.ReservationPending, model.PaymentUnpaid, 2*time.Hour)
+	add(6, model.ReservationPending, model.PaymentUnpaid, 45*time.Minute)
+
+	// Reservation 5 is paid while the expiry runs, it must be left alone
+	repo.beforeClose = func(reservationID int) {
+		if reservationID == 5 {
+			reservation := repo.reservations[5]
+			reservation.Status = model.ReservationPaid
+			repo.reservations[5] = reservation
+		}
+	}
+
+	usecase := &FlightUsecase{FlightRepo: repo, PaymentWindow: window, Logger: zap.NewNop()}
+	expired, err := usecase.ExpireUnpaidReservations()
+	if err != nil {
+		t.Fatalf("ExpireUnpaidReservations() error = %v", err)
+	}
+	if expired != 2 {
+		t.Errorf("ExpireUnpaidReservations() = %d, want 2", expired)
+	}
+
+	want := map[int]string{
+		1: model.ReservationExpired,
+		2: model.ReservationPending,
+		3: model.ReservationPaid,
+		4: model.ReservationCancelled,
+		5: model.ReservationPaid,
+		6: model.ReservationExpired,
+	}
+	for id, status := range want {
+		if got := repo.reservations[id].Status; got != status {
+			t.Errorf("reservation %d status = %s, want %s", id, got, status)
+		}
+	}
+}
@@ -0,0 +1,561 @@
+package usecase
+
+import (
+	"booking-engine/config"
+	"booking-engine/internal/model"
+	"booking-engine/internal/payment"
+	"booking-engine/internal/repository"
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// memoryFlightRepository keeps flights, seat layouts, reservations and the workflow outbox in memory. Only the
+// methods the tests need are implemented, calling any other method panics on the nil embedded interface.
+type memoryFlightRepository struct {
+	repository.FlightPersister
+
+	mu           sync.Mutex
+	flights      map[string]model.Flight
+	layouts      map[string]model.SeatLayout
+	occupied     map[string][]string
+	reservations map[int]model.Reservation
+	changes      []model.ReservationChange
+	outbox       map[int]model.WorkflowOutboxEntry
+	dispatched   map[int]bool
+	deployments  []model.WorkflowDeployment
+	nextID       int
+
+	// beforeClose runs right before a reservation is closed, to interleave a concurrent change
+	beforeClose func(reservationID int)
+	// beforePay runs right before a reservation is marked paid, to interleave a concurrent change
+	beforePay func(reservationID int)
+	// beforeUpdateInstance runs right before an instance key is recorded, to interleave a concurrent change
+	beforeUpdateInstance func(reservationID int, instanceKey int64)
+}
+
+func newMemoryFlightRepository(flight model.Flight, layout model.SeatLayout) *memoryFlightRepository {
+	return &memoryFlightRepository{
+		flights:      map[string]model.Flight{flight.FlightNumber: flight},
+		layouts:      map[string]model.SeatLayout{layout.AircraftType: layout},
+		occupied:     map[string][]string{},
+		reservations: map[int]model.Reservation{},
+		outbox:       map[int]model.WorkflowOutboxEntry{},
+		dispatched:   map[int]bool{},
+		nextID:       1,
+	}
+}
+
+func (r *memoryFlightRepository) GetFlightByID(flightID int) (model.Flight, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, flight := range r.flights {
+		if flight.FlightID == flightID {
+			return flight, nil
+		}
+	}
+	return model.Flight{}, model.ErrFlightNotFound
+}
+
+func (r *memoryFlightRepository) GetFlightByNumber(flightNumber string) (model.Flight, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	flight, ok := r.flights[flightNumber]
+	if !ok {
+		return model.Flight{}, model.ErrFlightNotFound
+	}
+	return flight, nil
+}
+
+func (r *memoryFlightRepository) GetSeatLayout(aircraftType string) (model.SeatLayout, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	layout, ok := r.layouts[aircraftType]
+	if !ok {
+		return model.SeatLayout{}, model.ErrSeatLayoutNotFound
+	}
+	return layout, nil
+}
+
+func (r *memoryFlightRepository) GetOccupiedSeats(flightNumber string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string(nil), r.occupied[flightNumber]...), nil
+}
+
+func (r *memoryFlightRepository) GetReservationByLocator(locator string) (model.Reservation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, reservation := range r.reservations {
+		if reservation.Locator == locator {
+			return reservation, nil
+		}
+	}
+	return model.Reservation{}, model.ErrReservationNotFound
+}
+
+func (r *memoryFlightRepository) LocatorExists(locator string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, reservation := range r.reservations {
+		if reservation.Locator == locator {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryFlightRepository) SaveBooking(locator string, booking model.BookingRequest, process model.ProcessDefinition) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := r.nextID
+	r.nextID++
+	r.reservations[id] = model.Reservation{
+		ReservationID: id,
+		Locator:       locator,
+		FlightNumber:  booking.FlightNumber,
+		PassengerID:   booking.Passengers[0].PassengerID,
+		SeatNumber:    booking.Passengers[0].SeatNumber,
+		Price:         booking.Price,
+		PaymentStatus: model.PaymentUnpaid,
+		Status:        model.ReservationPending,
+		Passengers:    reservationPassengers(booking.Passengers),
+	}
+
+	variables, err := json.Marshal(model.BookingVariables{ReservationID: id, Locator: locator})
+	if err != nil {
+		return 0, err
+	}
+	r.outbox[id] = model.WorkflowOutboxEntry{
+		OutboxID:       id,
+		ReservationID:  id,
+		ProcessID:      process.ProcessID,
+		ProcessVersion: process.Version,
+		Variables:      variables,
+	}
+	return id, nil
+}
+
+func (r *memoryFlightRepository) GetBookingByID(bookingID int) (model.Reservation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservation, ok := r.reservations[bookingID]
+	if !ok {
+		return model.Reservation{}, model.ErrReservationNotFound
+	}
+	return reservation, nil
+}
+
+func (r *memoryFlightRepository) UpdateInstanceID(reservationID int, instanceKey int64) error {
+	if r.beforeUpdateInstance != nil {
+		r.beforeUpdateInstance(reservationID, instanceKey)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservation := r.reservations[reservationID]
+	if isClosedReservation(reservation.Status) {
+		return model.ErrStatusConflict
+	}
+	reservation.InstanceKey = instanceKey
+	r.reservations[reservationID] = reservation
+	return nil
+}
+
+func (r *memoryFlightRepository) ModifyReservation(status string, change model.ReservationChange) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservation := r.reservations[change.ReservationID]
+	if reservation.Status != status {
+		return 0, model.ErrStatusConflict
+	}
+	for _, previous := range r.changes {
+		if change.ChargeID != "" && previous.ChargeID == change.ChargeID {
+			return 0, model.ErrChargeAlreadyUsed
+		}
+	}
+	reservation.FlightNumber = change.NewFlightNumber
+	reservation.Price += change.FareDifference + change.ChangeFee
+	for i, passenger := range change.Passengers {
+		reservation.Passengers[i].SeatNumber = passenger.NewSeat
+		reservation.Passengers[i].FareClass = passenger.NewFareClass
+		reservation.Passengers[i].FareBasis = passenger.FareBasis
+		reservation.Passengers[i].Price = passenger.NewPrice
+	}
+	r.reservations[change.ReservationID] = reservation
+
+	change.ChangeID = len(r.changes) + 1
+	r.changes = append(r.changes, change)
+	return change.ChangeID, nil
+}
+
+func (r *memoryFlightRepository) GetReservationChanges(reservationID int) ([]model.ReservationChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changes := []model.ReservationChange{}
+	for _, change := range r.changes {
+		if change.ReservationID == reservationID {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+func (r *memoryFlightRepository) CloseReservation(reservationID int, from string, to string, refundAmount float64, at time.Time) (int64, error) {
+	if r.beforeClose != nil {
+		r.beforeClose(reservationID)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservation := r.reservations[reservationID]
+	if reservation.Status != from {
+		return 0, model.ErrStatusConflict
+	}
+	reservation.Status = to
+	reservation.RefundAmount = refundAmount
+	r.reservations[reservationID] = reservation
+	return reservation.InstanceKey, nil
+}
+
+func (r *memoryFlightRepository) MarkReservationPaid(reservationID int, transactionID string, at time.Time) error {
+	if r.beforePay != nil {
+		r.beforePay(reservationID)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservation := r.reservations[reservationID]
+	if reservation.Status != model.ReservationPending {
+		return model.ErrStatusConflict
+	}
+	reservation.Status = model.ReservationPaid
+	reservation.PaymentStatus = model.PaymentPaid
+	reservation.TransactionID = transactionID
+	reservation.PaidAt = &at
+	r.reservations[reservationID] = reservation
+	return nil
+}
+
+func (r *memoryFlightRepository) UpdatePaymentReference(reservationID int, paymentReference string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservation := r.reservations[reservationID]
+	reservation.PaymentRef = paymentReference
+	r.reservations[reservationID] = reservation
+	return nil
+}
+
+func (r *memoryFlightRepository) UpdatePaymentStatus(reservationID int, paymentStatus string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservation := r.reservations[reservationID]
+	reservation.PaymentStatus = paymentStatus
+	r.reservations[reservationID] = reservation
+	return nil
+}
+
+func (r *memoryFlightRepository) GetExpirableReservations(createdBefore time.Time, limit int) ([]model.Reservation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var reservations []model.Reservation
+	for id := 1; id <= len(r.reservations) && len(reservations) < limit; id++ {
+		reservation, ok := r.reservations[id]
+		if ok && reservation.Status == model.ReservationPending && reservation.PaymentStatus == model.PaymentUnpaid &&
+			reservation.CreatedAt.Before(createdBefore) {
+			reservations = append(reservations, reservation)
+		}
+	}
+	return reservations, nil
+}
+
+func (r *memoryFlightRepository) GetWorkflowDeployments(resourceName string) ([]model.WorkflowDeployment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	latest := map[string]model.WorkflowDeployment{}
+	for _, deployment := range r.deployments {
+		if deployment.ResourceName == resourceName {
+			latest[deployment.ProcessID] = deployment
+		}
+	}
+	deployments := []model.WorkflowDeployment{}
+	for _, deployment := range latest {
+		deployments = append(deployments, deployment)
+	}
+	return deployments, nil
+}
+
+func (r *memoryFlightRepository) RecordWorkflowDeployments(deployments []model.WorkflowDeployment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.deployments = append(r.deployments, deployments...)
+	return nil
+}
+
+func (r *memoryFlightRepository) GetPendingOutboxEntries(now time.Time, limit int) ([]model.WorkflowOutboxEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var entries []model.WorkflowOutboxEntry
+	for id := 1; id < r.nextID && len(entries) < limit; id++ {
+		entry, ok := r.outbox[id]
+		if ok && !r.dispatched[id] && !entry.NextAttemptAt.After(now) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (r *memoryFlightRepository) ClaimOutboxEntry(entry model.WorkflowOutboxEntry, retryAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current := r.outbox[entry.OutboxID]
+	if r.dispatched[entry.OutboxID] || current.Attempts != entry.Attempts {
+		return false, nil
+	}
+	current.Attempts++
+	current.NextAttemptAt = retryAt
+	r.outbox[entry.OutboxID] = current
+	return true, nil
+}
+
+func (r *memoryFlightRepository) RecordOutboxError(outboxID int, message string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := r.outbox[outboxID]
+	entry.LastError = message
+	r.outbox[outboxID] = entry
+	return nil
+}
+
+func (r *memoryFlightRepository) MarkOutboxDispatched(outboxID int, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.dispatched[outboxID] = true
+	return nil
+}
+
+// memoryPassengerRepository serves passenger profiles from a map
+type memoryPassengerRepository struct {
+	repository.PassengerPersister
+
+	passengers map[int]model.Passenger
+}
+
+func (r *memoryPassengerRepository) GetPassengerByID(passengerID int) (model.Passenger, error) {
+	passenger, ok := r.passengers[passengerID]
+	if !ok {
+		return model.Passenger{}, model.ErrPassengerNotFound
+	}
+	return passenger, nil
+}
+
+// memoryPaymentProvider creates charges in the given status and keeps track of refunds
+type memoryPaymentProvider struct {
+	mu      sync.Mutex
+	status  string
+	charges map[string]model.Charge
+	refunds map[string]float64
+}
+
+func newMemoryPaymentProvider(status string) *memoryPaymentProvider {
+	return &memoryPaymentProvider{
+		status:  status,
+		charges: map[string]model.Charge{},
+		refunds: map[string]float64{},
+	}
+}
+
+var _ payment.PaymentProvider = (*memoryPaymentProvider)(nil)
+
+func (p *memoryPaymentProvider) CreateCharge(ctx context.Context, request model.ChargeRequest) (model.Charge, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	charge := model.Charge{
+		ChargeID:  "charge-" + strconv.Itoa(len(p.charges)+1),
+		Reference: request.Reference,
+		Method:    request.Method,
+		Channel:   request.Channel,
+		Status:    p.status,
+		Amount:    request.Amount,
+		Currency:  request.Currency,
+	}
+	p.charges[charge.ChargeID] = charge
+	return charge, nil
+}
+
+func (p *memoryPaymentProvider) QueryStatus(ctx context.Context, chargeID string) (model.Charge, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	charge, ok := p.charges[chargeID]
+	if !ok {
+		return model.Charge{}, model.ErrChargeNotFound
+	}
+	return charge, nil
+}
+
+func (p *memoryPaymentProvider) Refund(ctx context.Context, chargeID string, amount float64) (model.Refund, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	charge, ok := p.charges[chargeID]
+	if !ok {
+		return model.Refund{}, model.ErrChargeNotFound
+	}
+	if p.refunds[chargeID]+amount > charge.Amount {
+		return model.Refund{}, model.ErrRefundExceedsCharge
+	}
+	p.refunds[chargeID] += amount
+	return model.Refund{RefundID: "refund-" + chargeID, ChargeID: chargeID, Amount: amount, Status: model.ChargeSucceeded}, nil
+}
+
+func (p *memoryPaymentProvider) Void(ctx context.Context, chargeID string) (model.Charge, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	charge, ok := p.charges[chargeID]
+	if !ok {
+		return model.Charge{}, model.ErrChargeNotFound
+	}
+	if charge.Status == model.ChargeSucceeded {
+		return model.Charge{}, model.ErrChargeNotVoidable
+	}
+	charge.Status = model.ChargeVoided
+	p.charges[chargeID] = charge
+	return charge, nil
+}
+
+// memoryCacher is a config.Cacher without expiry
+type memoryCacher struct {
+	mu     sync.Mutex
+	values map[string]string
+
+	// beforeCompare runs at the start of the compare operations, to interleave a concurrent change
+	beforeCompare func(key string)
+}
+
+func newMemoryCacher() *memoryCacher {
+	return &memoryCacher{values: map[string]string{}}
+}
+
+var _ config.Cacher = (*memoryCacher)(nil)
+
+func (c *memoryCacher) Set(ctx context.Context, key string, value interface{}, duration time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.values[key] = toCacheString(value)
+	return nil
+}
+
+func (c *memoryCacher) SetNX(ctx context.Context, key string, value interface{}, duration time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.values[key]; ok {
+		return false, nil
+	}
+	c.values[key] = toCacheString(value)
+	return true, nil
+}
+
+func (c *memoryCacher) ExpireIfEqual(ctx context.Context, key string, value string, duration time.Duration) (bool, error) {
+	if c.beforeCompare != nil {
+		c.beforeCompare(key)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	current, ok := c.values[key]
+	return ok && current == value, nil
+}
+
+func (c *memoryCacher) Get(ctx context.Context, key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	value, ok := c.values[key]
+	if !ok {
+		return "", config.ErrCacheMiss
+	}
+	return value, nil
+}
+
+func (c *memoryCacher) Incr(ctx context.Context, key string, duration time.Duration) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	count, _ := strconv.ParseInt(c.values[key], 10, 64)
+	count++
+	c.values[key] = strconv.FormatInt(count, 10)
+	return count, nil
+}
+
+func (c *memoryCacher) Del(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.values, key)
+	return nil
+}
+
+func (c *memoryCacher) DelIfEqual(ctx context.Context, key string, value string) (bool, error) {
+	if c.beforeCompare != nil {
+		c.beforeCompare(key)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	current, ok := c.values[key]
+	if !ok || current != value {
+		return false, nil
+	}
+	delete(c.values, key)
+	return true, nil
+}
+
+// expire drops a key as if its expiry had passed
+func (c *memoryCacher) expire(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.values, key)
+}
+
+func toCacheString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	}
+	raw, _ := json.Marshal(value)
+	return string(raw)
+}
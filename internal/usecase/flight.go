@@ -1,24 +1,60 @@
 package usecase
 
 import (
+	"booking-engine/config"
 	"booking-engine/internal/model"
+	"booking-engine/internal/payment"
 	"booking-engine/internal/repository"
+	"booking-engine/internal/workflow"
 	"context"
-	"fmt"
-	"os"
-
-	"github.com/camunda-cloud/zeebe/clients/go/pkg/zbc"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Service handles business logic for flights and bookings
 type FlightUsecase struct {
-	FlightRepo repository.FlightPersister
+	FlightRepo    repository.FlightPersister
+	PassengerRepo repository.PassengerPersister
+	Cacher        config.Cacher
+	SeatHoldTTL   time.Duration
+	Location      *time.Location
+	Pricing       *PricingEngine
+	Quotes        *QuoteSigner
+	FareRules     map[string]model.FareRule
+	Workflow      workflow.WorkflowEngine
+	PaymentWindow time.Duration
+	Logger        config.Logger
+	Payments      payment.PaymentProvider
+	Process       model.ProcessDefinition
+
+	PaymentCallbackSecret []byte
 }
 
 type FlightExecutor interface {
 	GetFlightByID(id string) (*model.Flight, error)
+	SearchFlights(request model.FlightSearchRequest) (model.FlightSearchResult, error)
+	UpsertFlight(flight model.Flight) (*model.Flight, error)
+	GetSeatMap(id string) (model.SeatMap, error)
+	UpsertSeatLayout(layout model.SeatLayout) (model.SeatLayout, error)
+	HoldSeat(flightID string, seatNumber string, holderID string) (model.SeatHold, error)
+	ReleaseSeatHold(flightID string, seatNumber string, holderID string) error
+	GetFare(flightID string, request model.FareRequest) (model.Fare, error)
+	CreateQuote(request model.QuoteRequest) (model.Quote, error)
 	BookFlight(bookingRequest model.BookingRequest) (model.Reservation, error)
-	GetAllReservations() ([]model.Reservation, error)
+	GetAllReservations(request model.ReservationListRequest) (model.ReservationPage, error)
+	GetBookingByID(id int) (model.BookingDetail, error)
+	TransitionReservation(id int, status string) (model.Reservation, error)
+	CancelReservation(id int) (model.Reservation, error)
+	ModifyReservation(id int, request model.ModifyBookingRequest) (model.ReservationChange, error)
+	GetReservationChanges(id int) ([]model.ReservationChange, error)
+	GetReservationWorkflow(id int) (model.WorkflowStatus, error)
+	ExpireUnpaidReservations() (int, error)
+	DispatchWorkflowOutbox() (int, error)
+	HandlePaymentCallback(payload []byte, signature string) error
+	CreatePayment(id int, request model.PaymentRequest) (model.Charge, error)
+	GetPayment(id int) (model.Charge, error)
+	LookupBooking(request model.BookingLookupRequest, clientIP string) (model.BookingDetail, error)
 }
 
 // NewService creates a new instance of the flight service
@@ -26,75 +62,183 @@ func NewFlightUsecaseService(flightUsecase *FlightUsecase) FlightExecutor {
 	return flightUsecase
 }
 
-const ZeebeAddr = "0.0.0.0:26500"
+// DefaultProcessID is the BPMN process every reservation runs through unless another one is configured
+const DefaultProcessID = "fww-bpm"
 
-// GetFlightByID returns details of a specific flight by ID
+// GetFlightByID returns details of a specific flight by ID or flight number
 func (s *FlightUsecase) GetFlightByID(id string) (*model.Flight, error) {
-	return s.GetFlightByID(id)
+	var (
+		flight model.Flight
+		err    error
+	)
+
+	if flightID, convErr := strconv.Atoi(id); convErr == nil {
+		flight, err = s.FlightRepo.GetFlightByID(flightID)
+	} else {
+		flight, err = s.FlightRepo.GetFlightByNumber(strings.ToUpper(id))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &flight, nil
 }
 
-// BookFlight books a flight and returns the booking details
-func (s *FlightUsecase) BookFlight(bookingRequest model.BookingRequest) (model.Reservation, error) {
+// UpsertFlight creates or updates a flight in the catalog
+func (s *FlightUsecase) UpsertFlight(flight model.Flight) (*model.Flight, error) {
+	flight.FlightNumber = strings.ToUpper(strings.TrimSpace(flight.FlightNumber))
+	flight.Departure = strings.ToUpper(strings.TrimSpace(flight.Departure))
+	flight.Destination = strings.ToUpper(strings.TrimSpace(flight.Destination))
+	flight.AircraftType = strings.ToUpper(strings.TrimSpace(flight.AircraftType))
+	if flight.FlightNumber == "" || flight.Departure == "" || flight.Destination == "" ||
+		flight.DepartureTime.IsZero() || flight.Price < 0 || flight.AvailableSeats < 0 {
+		return nil, model.ErrInvalidFlight
+	}
 
-	reservationId, err := s.FlightRepo.SaveBooking(bookingRequest)
+	flightID, err := s.FlightRepo.UpsertFlight(flight)
 	if err != nil {
-		return model.Reservation{}, err
+		return nil, err
 	}
-	// For simplicity, let's assume the booking is successful
-	newBooking := model.Reservation{
-		ReservationID: reservationId,
-		FlightNumber:  bookingRequest.FlightNumber,
-		PassengerID:   bookingRequest.PassengerID,
-		SeatNumber:    bookingRequest.SeatNumber,
-		Price:         bookingRequest.Price,
+
+	// Read back the stored flight, an update keeps the booked inventory
+	stored, err := s.FlightRepo.GetFlightByID(flightID)
+	if err != nil {
+		return nil, err
 	}
 
-	fmt.Println(reservationId, err)
+	return &stored, nil
+}
 
-	gatewayAddr := os.Getenv("ZEEBE_ADDRESS")
-	plainText := false
+// BookFlight books a flight for every passenger of the request under a single reservation and returns the booking details.
+// Nothing is kept of a booking that fails, the reservation, its seats and its process instance are saved in one transaction.
+func (s *FlightUsecase) BookFlight(bookingRequest model.BookingRequest) (model.Reservation, error) {
+	reservation, err := s.bookFlight(bookingRequest)
+	return reservation, classifyBookingError("book flight", err)
+}
 
-	if gatewayAddr == "" {
-		gatewayAddr = ZeebeAddr
-		plainText = true
+func (s *FlightUsecase) bookFlight(bookingRequest model.BookingRequest) (model.Reservation, error) {
+	bookingRequest, err := normalizeBookingRequest(bookingRequest)
+	if err != nil {
+		return model.Reservation{}, err
 	}
 
-	zbClient, err := zbc.NewClient(&zbc.ClientConfig{
-		GatewayAddress:         gatewayAddr,
-		UsePlaintextConnection: plainText,
-	})
-
+	flight, err := s.FlightRepo.GetFlightByNumber(bookingRequest.FlightNumber)
 	if err != nil {
-		panic(err)
+		return model.Reservation{}, err
 	}
 
-	ctx := context.Background()
-	// variables := make(map[model.BookingVariables]interface{})
-	variables := model.BookingVariables{
-		ReservationID: reservationId,
-		StatusPayment: false,
+	// The reservation carries the names of the passenger profiles as they were at booking time,
+	// the passenger type follows from the age at departure rather than from the request
+	for i, passenger := range bookingRequest.Passengers {
+		profile, err := s.PassengerRepo.GetPassengerByID(passenger.PassengerID)
+		if err != nil {
+			return model.Reservation{}, err
+		}
+		passengerType, err := passengerTypeAt(profile.DateOfBirth, flight.DepartureTime.In(s.location()))
+		if err != nil {
+			return model.Reservation{}, err
+		}
+		if passenger.PassengerType != "" && passenger.PassengerType != passengerType {
+			return model.Reservation{}, model.ErrInvalidPassengerType
+		}
+		bookingRequest.Passengers[i].FirstName = profile.FirstName
+		bookingRequest.Passengers[i].LastName = profile.LastName
+		bookingRequest.Passengers[i].PassengerType = passengerType
+	}
+	if err := checkPassengerMix(bookingRequest.Passengers); err != nil {
+		return model.Reservation{}, err
 	}
 
-	request, err := zbClient.NewCreateInstanceCommand().BPMNProcessId("fww-bpm").LatestVersion().VariablesFromObject(variables)
-	if err != nil {
-		panic(err)
+	var quote *model.QuoteClaims
+	if bookingRequest.QuoteToken != "" {
+		quote, err = s.redeemQuote(bookingRequest.QuoteToken, flight, bookingRequest.Passengers)
+		if err != nil {
+			return model.Reservation{}, err
+		}
 	}
 
-	resp, err := request.Send(ctx)
-	fmt.Println("BLI", resp.ProcessInstanceKey)
+	total := 0.0
+	for i, passenger := range bookingRequest.Passengers {
+		var seat *model.Seat
+		if passenger.SeatNumber != "" {
+			found, err := s.findSeat(flight, passenger.SeatNumber)
+			if err != nil {
+				return model.Reservation{}, err
+			}
+			if err := s.checkSeatHold(flight.FlightNumber, passenger.SeatNumber, bookingRequest.HolderID); err != nil {
+				return model.Reservation{}, err
+			}
+			seat = &found
+		}
+
+		fare, err := s.pricePassenger(flight, seat, passenger, quote)
+		if err != nil {
+			return model.Reservation{}, err
+		}
+		bookingRequest.Passengers[i].FareClass = fare.FareClass
+		bookingRequest.Passengers[i].Price = fare.Total
+		total += fare.Total
+	}
+	if bookingRequest.Price != 0 && bookingRequest.Price != total {
+		return model.Reservation{}, model.ErrPriceMismatch
+	}
+	bookingRequest.Price = total
+
+	var (
+		locator       string
+		reservationId int
+	)
+	for attempt := 0; attempt < locatorMaxAttempts; attempt++ {
+		locator, err = s.newLocator()
+		if err != nil {
+			return model.Reservation{}, err
+		}
+		reservationId, err = s.FlightRepo.SaveBooking(locator, bookingRequest, s.processDefinition())
+		if err != model.ErrDuplicateLocator {
+			break
+		}
+	}
 	if err != nil {
-		panic(err)
+		return model.Reservation{}, err
+	}
+	// The seats are sold now, so the holds have served their purpose
+	for _, passenger := range bookingRequest.Passengers {
+		if passenger.SeatNumber != "" {
+			_ = s.Cacher.Del(context.Background(), seatHoldKey(flight.FlightNumber, passenger.SeatNumber))
+		}
+	}
+
+	// The outbox dispatcher starts the process instance, its key is recorded on the reservation shortly after
+	lead := bookingRequest.Passengers[0]
+	newBooking := model.Reservation{
+		ReservationID: reservationId,
+		Locator:       locator,
+		FlightNumber:  bookingRequest.FlightNumber,
+		PassengerID:   lead.PassengerID,
+		SeatNumber:    lead.SeatNumber,
+		Price:         bookingRequest.Price,
+		PaymentStatus: model.PaymentUnpaid,
+		Status:        model.ReservationPending,
+		Passengers:    reservationPassengers(bookingRequest.Passengers),
 	}
 
-	err = s.FlightRepo.UpdateInstanceID(reservationId, resp.ProcessInstanceKey)
 	return newBooking, nil
 }
 
-// GetBookings returns all flight bookings
-func (s *FlightUsecase) GetAllReservations() ([]model.Reservation, error) {
-	reservations, err := s.FlightRepo.GetAllReservations()
+// GetBookingByID returns a reservation together with the flight it was booked on
+func (s *FlightUsecase) GetBookingByID(id int) (model.BookingDetail, error) {
+	reservation, err := s.FlightRepo.GetBookingByID(id)
 	if err != nil {
-		return []model.Reservation{}, err
+		return model.BookingDetail{}, err
 	}
-	return reservations, nil
+
+	flight, err := s.FlightRepo.GetFlightByNumber(reservation.FlightNumber)
+	if err != nil {
+		return model.BookingDetail{}, err
+	}
+
+	return model.BookingDetail{
+		Reservation: reservation,
+		Flight:      flight,
+	}, nil
 }
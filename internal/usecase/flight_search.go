@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"strings"
+	"time"
+)
+
+const (
+	searchDefaultLimit = 20
+	searchMaxLimit     = 100
+	searchDateLayout   = "2006-01-02"
+	searchClockLayout  = "15:04"
+
+	// DefaultTimezone is where the departure airports are, search dates and times are local to it
+	DefaultTimezone = "Asia/Jakarta"
+)
+
+// SearchFlights returns a page of flights matching the route, date and filters of the request
+func (s *FlightUsecase) SearchFlights(request model.FlightSearchRequest) (model.FlightSearchResult, error) {
+	filter, err := buildFlightFilter(request, s.location())
+	if err != nil {
+		return model.FlightSearchResult{}, err
+	}
+
+	flights, total, err := s.FlightRepo.SearchFlights(filter)
+	if err != nil {
+		return model.FlightSearchResult{}, err
+	}
+
+	return model.FlightSearchResult{
+		Data:  flights,
+		Page:  filter.Offset/filter.Limit + 1,
+		Limit: filter.Limit,
+		Total: total,
+	}, nil
+}
+
+// buildFlightFilter validates the search request, reading its date and departure window as local time in loc
+func buildFlightFilter(request model.FlightSearchRequest, loc *time.Location) (model.FlightFilter, error) {
+	filter := model.FlightFilter{
+		From:     strings.ToUpper(strings.TrimSpace(request.From)),
+		To:       strings.ToUpper(strings.TrimSpace(request.To)),
+		MinSeats: request.Passengers,
+		MaxPrice: request.MaxPrice,
+		Limit:    request.Limit,
+	}
+
+	if request.Passengers < 0 || request.MaxPrice < 0 || request.Page < 0 || request.Limit < 0 {
+		return filter, model.ErrInvalidSearch
+	}
+	if filter.MinSeats == 0 {
+		filter.MinSeats = 1
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = searchDefaultLimit
+	}
+	if filter.Limit > searchMaxLimit {
+		filter.Limit = searchMaxLimit
+	}
+	page := request.Page
+	if page == 0 {
+		page = 1
+	}
+	filter.Offset = (page - 1) * filter.Limit
+
+	switch strings.ToLower(request.Sort) {
+	case "", model.FlightSortDepartureTime:
+		filter.SortBy = model.FlightSortDepartureTime
+	case model.FlightSortPrice:
+		filter.SortBy = model.FlightSortPrice
+	default:
+		return filter, model.ErrInvalidSearch
+	}
+	switch strings.ToLower(request.Order) {
+	case "", "asc":
+	case "desc":
+		filter.SortDesc = true
+	default:
+		return filter, model.ErrInvalidSearch
+	}
+
+	if request.Date == "" {
+		// The departure window is a time of day, so it needs a date to apply to
+		if request.DepartAfter != "" || request.DepartBefore != "" {
+			return filter, model.ErrInvalidSearch
+		}
+		return filter, nil
+	}
+
+	day, err := time.ParseInLocation(searchDateLayout, request.Date, loc)
+	if err != nil {
+		return filter, model.ErrInvalidSearch
+	}
+	filter.DepartureFrom = day
+	filter.DepartureTo = day.AddDate(0, 0, 1)
+
+	if request.DepartAfter != "" {
+		after, err := clockOnDay(day, request.DepartAfter)
+		if err != nil {
+			return filter, model.ErrInvalidSearch
+		}
+		filter.DepartureFrom = after
+	}
+	if request.DepartBefore != "" {
+		before, err := clockOnDay(day, request.DepartBefore)
+		if err != nil {
+			return filter, model.ErrInvalidSearch
+		}
+		filter.DepartureTo = before
+	}
+	if !filter.DepartureFrom.Before(filter.DepartureTo) {
+		return filter, model.ErrInvalidSearch
+	}
+
+	return filter, nil
+}
+
+// clockOnDay combines a HH:MM clock value with the given day
+func clockOnDay(day time.Time, clock string) (time.Time, error) {
+	t, err := time.Parse(searchClockLayout, clock)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, day.Location()), nil
+}
+
+// location returns the timezone search dates are given in. Departure times are written from Go and the
+// driver stores them in UTC, so local dates have to be converted before they are compared.
+func (s *FlightUsecase) location() *time.Location {
+	if s.Location != nil {
+		return s.Location
+	}
+	if loc, err := time.LoadLocation(DefaultTimezone); err == nil {
+		return loc
+	}
+	// Western Indonesia Time has no daylight saving, a fixed offset stands in when no zone database is installed
+	return time.FixedZone("WIB", 7*60*60)
+}
@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"testing"
+	"time"
+)
+
+func TestBuildFlightFilter(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+
+	tests := []struct {
+		name     string
+		request  model.FlightSearchRequest
+		wantFrom time.Time
+		wantTo   time.Time
+		wantErr  error
+	}{
+		{
+			name:     "whole local day",
+			request:  model.FlightSearchRequest{From: "cgk", To: "dps", Date: "2026-03-01"},
+			wantFrom: time.Date(2026, 2, 28, 17, 0, 0, 0, time.UTC),
+			wantTo:   time.Date(2026, 3, 1, 17, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "departure window in local time",
+			request:  model.FlightSearchRequest{Date: "2026-03-01", DepartAfter: "06:30", DepartBefore: "12:00"},
+			wantFrom: time.Date(2026, 2, 28, 23, 30, 0, 0, time.UTC),
+			wantTo:   time.Date(2026, 3, 1, 5, 0, 0, 0, time.UTC),
+		},
+		{name: "no date", request: model.FlightSearchRequest{From: "CGK"}},
+		{name: "window without a date", request: model.FlightSearchRequest{DepartAfter: "06:00"}, wantErr: model.ErrInvalidSearch},
+		{name: "window ends before it starts", request: model.FlightSearchRequest{Date: "2026-03-01", DepartAfter: "12:00", DepartBefore: "06:00"}, wantErr: model.ErrInvalidSearch},
+		{name: "malformed date", request: model.FlightSearchRequest{Date: "01-03-2026"}, wantErr: model.ErrInvalidSearch},
+		{name: "malformed clock", request: model.FlightSearchRequest{Date: "2026-03-01", DepartAfter: "6pm"}, wantErr: model.ErrInvalidSearch},
+		{name: "unknown sort", request: model.FlightSearchRequest{Sort: "duration"}, wantErr: model.ErrInvalidSearch},
+		{name: "negative page", request: model.FlightSearchRequest{Page: -1}, wantErr: model.ErrInvalidSearch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := buildFlightFilter(tt.request, jakarta)
+			if err != tt.wantErr {
+				t.Fatalf("buildFlightFilter() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !filter.DepartureFrom.Equal(tt.wantFrom) || !filter.DepartureTo.Equal(tt.wantTo) {
+				t.Errorf("buildFlightFilter() departure = %v to %v, want %v to %v",
+					filter.DepartureFrom, filter.DepartureTo, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+)
+
+const (
+	locatorLength = 6
+	// locatorAlphabet leaves out 0/O, 1/I/L which are easily confused when read out loud or over the phone
+	locatorAlphabet    = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+	locatorMaxAttempts = 5
+)
+
+var errLocatorExhausted = errors.New("could not generate an unused record locator")
+
+// newLocator returns a random record locator that is not used by any reservation yet.
+// The unique key on reservations.locator still guards against a concurrent booking picking the same one.
+func (s *FlightUsecase) newLocator() (string, error) {
+	for attempt := 0; attempt < locatorMaxAttempts; attempt++ {
+		locator, err := randomLocator()
+		if err != nil {
+			return "", err
+		}
+
+		exists, err := s.FlightRepo.LocatorExists(locator)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return locator, nil
+		}
+	}
+
+	return "", errLocatorExhausted
+}
+
+func randomLocator() (string, error) {
+	max := big.NewInt(int64(len(locatorAlphabet)))
+	locator := make([]byte, locatorLength)
+	for i := range locator {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		locator[i] = locatorAlphabet[n.Int64()]
+	}
+
+	return string(locator), nil
+}
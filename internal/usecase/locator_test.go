@@ -0,0 +1,28 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRandomLocator(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 1000; i++ {
+		locator, err := randomLocator()
+		if err != nil {
+			t.Fatalf("randomLocator() error = %v", err)
+		}
+		if len(locator) != locatorLength {
+			t.Fatalf("randomLocator() = %q, want %d characters", locator, locatorLength)
+		}
+		if strings.Trim(locator, locatorAlphabet) != "" {
+			t.Fatalf("randomLocator() = %q, has characters outside %q", locator, locatorAlphabet)
+		}
+		seen[locator] = true
+	}
+
+	// 31^6 possible locators, a thousand draws practically never collide
+	if len(seen) < 990 {
+		t.Errorf("randomLocator() returned only %d distinct locators out of 1000", len(seen))
+	}
+}
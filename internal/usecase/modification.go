@@ -0,0 +1,292 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"booking-engine/internal/payment"
+	"context"
+	"strings"
+	"time"
+)
+
+// ModifyReservation moves passengers of a reservation to other seats and optionally to another flight on the same
+// route. Upgrades are charged the fare difference, downgrades are not refunded, and a flight change costs the change
+// fee of each seated passenger's fare basis, infants on a lap change along with their adult. The reservation keeps
+// its record locator. A change that costs extra on a paid booking applies once its charge is paid, on a pending
+// booking the charge being paid is replaced by one for the new price.
+func (s *FlightUsecase) ModifyReservation(id int, request model.ModifyBookingRequest) (model.ReservationChange, error) {
+	reservation, err := s.FlightRepo.GetBookingByID(id)
+	if err != nil {
+		return model.ReservationChange{}, err
+	}
+	if !isModifiable(reservation.Status) {
+		return model.ReservationChange{}, model.ErrBookingNotModifiable
+	}
+
+	current, err := s.FlightRepo.GetFlightByNumber(reservation.FlightNumber)
+	if err != nil {
+		return model.ReservationChange{}, err
+	}
+	now := time.Now()
+	if !now.Before(current.DepartureTime) {
+		return model.ReservationChange{}, model.ErrFlightDeparted
+	}
+
+	target := current
+	flightNumber := strings.ToUpper(strings.TrimSpace(request.FlightNumber))
+	flightChange := flightNumber != "" && flightNumber != current.FlightNumber
+	if flightChange {
+		target, err = s.FlightRepo.GetFlightByNumber(flightNumber)
+		if err != nil {
+			return model.ReservationChange{}, err
+		}
+		if target.Departure != current.Departure || target.Destination != current.Destination {
+			return model.ReservationChange{}, model.ErrInvalidModification
+		}
+		if !now.Before(target.DepartureTime) {
+			return model.ReservationChange{}, model.ErrFlightDeparted
+		}
+	}
+
+	seatChanges, err := collectSeatChanges(reservation, request.Seats)
+	if err != nil {
+		return model.ReservationChange{}, err
+	}
+	if !flightChange && len(seatChanges) == 0 {
+		return model.ReservationChange{}, model.ErrInvalidModification
+	}
+
+	change := model.ReservationChange{
+		ReservationID:   reservation.ReservationID,
+		OldFlightNumber: current.FlightNumber,
+		NewFlightNumber: target.FlightNumber,
+		CreatedAt:       now,
+	}
+	rules := s.fareRules()
+	for _, passenger := range reservation.Passengers {
+		passengerChange, err := s.changePassenger(target, passenger, seatChanges, flightChange, request.HolderID)
+		if err != nil {
+			return model.ReservationChange{}, err
+		}
+		change.FareDifference += passengerChange.NewPrice - passengerChange.OldPrice
+		if flightChange && passenger.SeatNumber != "" {
+			change.ChangeFee += rules[fareBasis(passenger)].ChangeFee
+		}
+		change.Passengers = append(change.Passengers, passengerChange)
+	}
+	change.AmountDue = change.FareDifference + change.ChangeFee
+
+	var recharge func() *model.Charge
+	if change.AmountDue > 0 {
+		switch {
+		case reservation.PaymentStatus == model.PaymentPaid:
+			charge, err := s.chargeChange(reservation, change.AmountDue, request)
+			if err != nil {
+				return model.ReservationChange{}, err
+			}
+			if charge.Status != model.ChargeSucceeded {
+				// Nothing changes until the charge is paid, the change is sent again with its charge ID then
+				change.Charge = &charge
+				return change, nil
+			}
+			change.ChargeID = charge.ChargeID
+		case reservation.PaymentRef != "":
+			// The pending charge is for the old price, it must not be paid any more
+			if recharge, err = s.replaceCharge(reservation); err != nil {
+				return model.ReservationChange{}, err
+			}
+		}
+	}
+
+	changeID, err := s.FlightRepo.ModifyReservation(reservation.Status, change)
+	if recharge != nil {
+		change.Charge = recharge()
+	}
+	if err != nil {
+		if change.ChargeID != "" && err != model.ErrChargeAlreadyUsed {
+			s.returnPayment(reservation, change.ChargeID, change.AmountDue)
+		}
+		return model.ReservationChange{}, err
+	}
+	change.ChangeID = changeID
+
+	for _, passenger := range change.Passengers {
+		if passenger.NewSeat != "" {
+			_ = s.Cacher.Del(context.Background(), seatHoldKey(target.FlightNumber, passenger.NewSeat))
+		}
+	}
+
+	return change, nil
+}
+
+// chargeChange returns the charge paying for a change of a paid booking, either the charge named in the request
+// or a new one through the requested payment method
+func (s *FlightUsecase) chargeChange(reservation model.Reservation, amount float64, request model.ModifyBookingRequest) (model.Charge, error) {
+	ctx := context.Background()
+
+	if request.ChargeID != "" {
+		changes, err := s.FlightRepo.GetReservationChanges(reservation.ReservationID)
+		if err != nil {
+			return model.Charge{}, err
+		}
+		for _, change := range changes {
+			if change.ChargeID == request.ChargeID {
+				return model.Charge{}, model.ErrChargeAlreadyUsed
+			}
+		}
+
+		charge, err := s.Payments.QueryStatus(ctx, request.ChargeID)
+		if err != nil {
+			return model.Charge{}, err
+		}
+		if charge.Reference != changeChargeReference(reservation.Locator) {
+			return model.Charge{}, model.ErrChargeNotFound
+		}
+		if charge.Status == model.ChargeFailed || charge.Status == model.ChargeVoided {
+			return model.Charge{}, model.ErrChangeNotPaid
+		}
+		if charge.Status == model.ChargeSucceeded && charge.Amount != amount {
+			// The price moved since the charge was created, the customer gets the money back and starts over
+			s.returnPayment(reservation, charge.ChargeID, charge.Amount)
+			return model.Charge{}, model.ErrPaymentAmountMismatch
+		}
+		return charge, nil
+	}
+
+	if request.Payment == nil {
+		return model.Charge{}, model.ErrChangeNotPaid
+	}
+	method := strings.ToLower(strings.TrimSpace(request.Payment.Method))
+	if !payment.ValidMethod(method) {
+		return model.Charge{}, model.ErrInvalidPaymentMethod
+	}
+	charge, err := s.Payments.CreateCharge(ctx, model.ChargeRequest{
+		Reference: changeChargeReference(reservation.Locator),
+		Amount:    amount,
+		Currency:  model.CurrencyIDR,
+		Method:    method,
+		Channel:   strings.ToLower(strings.TrimSpace(request.Payment.Channel)),
+	})
+	if err != nil {
+		return model.Charge{}, err
+	}
+	if charge.Status == model.ChargeFailed {
+		return model.Charge{}, model.ErrChangeNotPaid
+	}
+	return charge, nil
+}
+
+// changeChargeReference is the reference of the charges paying for changes of a booking, telling them apart from
+// a second payment of the booking itself
+func changeChargeReference(locator string) string {
+	return locator + "-CHANGE"
+}
+
+// GetReservationChanges returns the change history of a reservation
+func (s *FlightUsecase) GetReservationChanges(id int) ([]model.ReservationChange, error) {
+	if _, err := s.FlightRepo.GetBookingByID(id); err != nil {
+		return nil, err
+	}
+	return s.FlightRepo.GetReservationChanges(id)
+}
+
+// changePassenger works out the new seat, cabin and price of one passenger of a modified reservation
+func (s *FlightUsecase) changePassenger(target model.Flight, passenger model.ReservationPassenger, seatChanges map[int]string,
+	flightChange bool, holderID string) (model.PassengerChange, error) {
+	change := model.PassengerChange{
+		PassengerID:  passenger.PassengerID,
+		OldSeat:      passenger.SeatNumber,
+		NewSeat:      passenger.SeatNumber,
+		OldFareClass: passenger.FareClass,
+		NewFareClass: passenger.FareClass,
+		FareBasis:    fareBasis(passenger),
+		OldPrice:     passenger.Price,
+		NewPrice:     passenger.Price,
+	}
+
+	seatNumber, moved := seatChanges[passenger.PassengerID]
+	if moved {
+		change.NewSeat = seatNumber
+	} else if flightChange && passenger.SeatNumber != "" {
+		// A seat on the old flight means nothing on the new one
+		return change, model.ErrInvalidModification
+	}
+
+	if change.NewSeat != "" {
+		seat, err := s.findSeat(target, change.NewSeat)
+		if err != nil {
+			return change, err
+		}
+		if flightChange || change.NewSeat != change.OldSeat {
+			if err := s.checkSeatHold(target.FlightNumber, change.NewSeat, holderID); err != nil {
+				return change, err
+			}
+		}
+		change.NewFareClass = seat.Cabin
+	}
+
+	if flightChange || change.NewFareClass != change.OldFareClass {
+		fare, err := s.pricing().Quote(target, change.NewFareClass, passenger.PassengerType)
+		if err != nil {
+			return change, err
+		}
+		// A downgrade keeps the price, and with it the fare rules, of the class that was paid for
+		if fare.Total > change.OldPrice {
+			change.NewPrice = fare.Total
+			change.FareBasis = change.NewFareClass
+		}
+	}
+
+	return change, nil
+}
+
+// collectSeatChanges validates the requested seats, every passenger may appear once and no seat may be used twice
+func collectSeatChanges(reservation model.Reservation, requested []model.SeatChange) (map[int]string, error) {
+	passengers := map[int]model.ReservationPassenger{}
+	for _, passenger := range reservation.Passengers {
+		passengers[passenger.PassengerID] = passenger
+	}
+
+	changes := map[int]string{}
+	for _, seatChange := range requested {
+		passenger, ok := passengers[seatChange.PassengerID]
+		seatNumber := normalizeSeatNumber(seatChange.SeatNumber)
+		// Infants travelling on a lap have no seat to change
+		if !ok || seatNumber == "" || passenger.SeatNumber == "" {
+			return nil, model.ErrInvalidModification
+		}
+		if _, duplicate := changes[seatChange.PassengerID]; duplicate {
+			return nil, model.ErrInvalidModification
+		}
+		changes[seatChange.PassengerID] = seatNumber
+	}
+
+	used := map[string]bool{}
+	for _, passenger := range reservation.Passengers {
+		seatNumber, moved := changes[passenger.PassengerID]
+		if !moved {
+			seatNumber = passenger.SeatNumber
+		}
+		if seatNumber == "" {
+			continue
+		}
+		if used[seatNumber] {
+			return nil, model.ErrInvalidModification
+		}
+		used[seatNumber] = true
+	}
+
+	return changes, nil
+}
+
+// fareBasis returns the fare class whose rules apply to the passenger, reservations made before fare bases were
+// recorded were never downgraded and use their fare class
+func fareBasis(passenger model.ReservationPassenger) string {
+	if passenger.FareBasis == "" {
+		return passenger.FareClass
+	}
+	return passenger.FareBasis
+}
+
+func isModifiable(status string) bool {
+	return status == model.ReservationPending || status == model.ReservationPaid || status == model.ReservationTicketed
+}
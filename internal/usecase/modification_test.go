@@ -0,0 +1,346 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestModifyReservation(t *testing.T) {
+	departure := time.Now().Add(7 * 24 * time.Hour)
+	flight := model.Flight{FlightID: 1, FlightNumber: "GA402", Departure: "CGK", Destination: "DPS", AircraftType: "A320",
+		DepartureTime: departure, Price: 1000000, AvailableSeats: 10}
+	later := flight
+	later.FlightID, later.FlightNumber, later.DepartureTime = 2, "GA404", departure.Add(6*time.Hour)
+	otherRoute := flight
+	otherRoute.FlightID, otherRoute.FlightNumber, otherRoute.Destination = 3, "GA406", "SUB"
+	layout := model.SeatLayout{AircraftType: "A320", Rows: 10, Columns: "ABC",
+		Cabins: []model.Cabin{{Class: model.CabinBusiness, FirstRow: 1, LastRow: 2}}}
+
+	pricing := NewPricingEngine(DefaultPricingRules())
+	economy, _ := pricing.Quote(flight, model.CabinEconomy, model.PassengerAdult)
+	business, _ := pricing.Quote(flight, model.CabinBusiness, model.PassengerAdult)
+	infant, _ := pricing.Quote(flight, model.CabinEconomy, model.PassengerInfant)
+	changeFee := DefaultFareRules()[model.CabinEconomy].ChangeFee
+
+	adultAndInfant := []model.ReservationPassenger{
+		{PassengerID: 1, PassengerType: model.PassengerAdult, SeatNumber: "5A", FareClass: model.CabinEconomy, Price: economy.Total},
+		{PassengerID: 2, PassengerType: model.PassengerInfant, FareClass: model.CabinEconomy, Price: infant.Total},
+	}
+	businessAdult := []model.ReservationPassenger{
+		{PassengerID: 1, PassengerType: model.PassengerAdult, SeatNumber: "1A", FareClass: model.CabinBusiness, Price: business.Total},
+	}
+
+	tests := []struct {
+		name          string
+		status        string
+		paymentStatus string
+		passengers    []model.ReservationPassenger
+		request       model.ModifyBookingRequest
+		wantErr       error
+		wantFeeDue    float64
+		wantFareDiff  float64
+		wantFareBasis string
+	}{
+		{
+			name:          "seat change in the same cabin of a paid booking",
+			status:        model.ReservationPaid,
+			paymentStatus: model.PaymentPaid,
+			passengers:    adultAndInfant,
+			request:       model.ModifyBookingRequest{Seats: []model.SeatChange{{PassengerID: 1, SeatNumber: "6b"}}},
+			wantFareBasis: model.CabinEconomy,
+		},
+		{
+			name:          "upgrade is charged the fare difference",
+			status:        model.ReservationPending,
+			passengers:    adultAndInfant,
+			request:       model.ModifyBookingRequest{Seats: []model.SeatChange{{PassengerID: 1, SeatNumber: "1A"}}},
+			wantFareDiff:  business.Total - economy.Total,
+			wantFareBasis: model.CabinBusiness,
+		},
+		{
+			name:          "downgrade keeps the price and fare basis",
+			status:        model.ReservationPaid,
+			paymentStatus: model.PaymentPaid,
+			passengers:    businessAdult,
+			request:       model.ModifyBookingRequest{Seats: []model.SeatChange{{PassengerID: 1, SeatNumber: "5A"}}},
+			wantFareBasis: model.CabinBusiness,
+		},
+		{
+			name:          "flight change charges the change fee once for an adult with a lap infant",
+			status:        model.ReservationPending,
+			passengers:    adultAndInfant,
+			request:       model.ModifyBookingRequest{FlightNumber: "ga404", Seats: []model.SeatChange{{PassengerID: 1, SeatNumber: "5A"}}},
+			wantFeeDue:    changeFee,
+			wantFareBasis: model.CabinEconomy,
+		},
+		{
+			name:          "flight change of a paid booking without a payment",
+			status:        model.ReservationPaid,
+			paymentStatus: model.PaymentPaid,
+			passengers:    adultAndInfant,
+			request:       model.ModifyBookingRequest{FlightNumber: "GA404", Seats: []model.SeatChange{{PassengerID: 1, SeatNumber: "5A"}}},
+			wantErr:       model.ErrChangeNotPaid,
+		},
+		{
+			name:       "flight change without a new seat",
+			status:     model.ReservationPending,
+			passengers: adultAndInfant,
+			request:    model.ModifyBookingRequest{FlightNumber: "GA404"},
+			wantErr:    model.ErrInvalidModification,
+		},
+		{
+			name:       "flight on another route",
+			status:     model.ReservationPending,
+			passengers: adultAndInfant,
+			request:    model.ModifyBookingRequest{FlightNumber: "GA406", Seats: []model.SeatChange{{PassengerID: 1, SeatNumber: "5A"}}},
+			wantErr:    model.ErrInvalidModification,
+		},
+		{
+			name:       "seat for a lap infant",
+			status:     model.ReservationPending,
+			passengers: adultAndInfant,
+			request:    model.ModifyBookingRequest{Seats: []model.SeatChange{{PassengerID: 2, SeatNumber: "5B"}}},
+			wantErr:    model.ErrInvalidModification,
+		},
+		{
+			name:       "nothing to change",
+			status:     model.ReservationPending,
+			passengers: adultAndInfant,
+			wantErr:    model.ErrInvalidModification,
+		},
+		{
+			name:       "cancelled booking",
+			status:     model.ReservationCancelled,
+			passengers: adultAndInfant,
+			request:    model.ModifyBookingRequest{Seats: []model.SeatChange{{PassengerID: 1, SeatNumber: "6B"}}},
+			wantErr:    model.ErrBookingNotModifiable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryFlightRepository(flight, layout)
+			repo.flights[later.FlightNumber] = later
+			repo.flights[otherRoute.FlightNumber] = otherRoute
+			price := 0.0
+			for _, passenger := range tt.passengers {
+				price += passenger.Price
+			}
+			paymentStatus := tt.paymentStatus
+			if paymentStatus == "" {
+				paymentStatus = model.PaymentUnpaid
+			}
+			repo.reservations[1] = model.Reservation{
+				ReservationID: 1,
+				FlightNumber:  flight.FlightNumber,
+				Price:         price,
+				PaymentStatus: paymentStatus,
+				Status:        tt.status,
+				Passengers:    append([]model.ReservationPassenger(nil), tt.passengers...),
+			}
+			usecase := &FlightUsecase{FlightRepo: repo, Cacher: newMemoryCacher()}
+
+			change, err := usecase.ModifyReservation(1, tt.request)
+			if err != tt.wantErr {
+				t.Fatalf("ModifyReservation() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(repo.changes) != 0 {
+					t.Errorf("ModifyReservation() recorded %d changes", len(repo.changes))
+				}
+				return
+			}
+
+			if change.ChangeFee != tt.wantFeeDue || change.FareDifference != tt.wantFareDiff {
+				t.Errorf("ModifyReservation() fee = %v, fare difference = %v, want %v and %v",
+					change.ChangeFee, change.FareDifference, tt.wantFeeDue, tt.wantFareDiff)
+			}
+			if change.AmountDue != tt.wantFeeDue+tt.wantFareDiff {
+				t.Errorf("ModifyReservation() amount due = %v, want %v", change.AmountDue, tt.wantFeeDue+tt.wantFareDiff)
+			}
+			if basis := change.Passengers[0].FareBasis; basis != tt.wantFareBasis {
+				t.Errorf("ModifyReservation() fare basis = %q, want %q", basis, tt.wantFareBasis)
+			}
+			if got := repo.reservations[1].Price; got != price+change.AmountDue {
+				t.Errorf("reservation price = %v, want %v", got, price+change.AmountDue)
+			}
+		})
+	}
+}
+
+func TestModifyReservationPayment(t *testing.T) {
+	departure := time.Now().Add(7 * 24 * time.Hour)
+	flight := model.Flight{FlightID: 1, FlightNumber: "GA402", Departure: "CGK", Destination: "DPS", AircraftType: "A320",
+		DepartureTime: departure, Price: 1000000, AvailableSeats: 10}
+	later := flight
+	later.FlightID, later.FlightNumber, later.DepartureTime = 2, "GA404", departure.Add(6*time.Hour)
+	layout := model.SeatLayout{AircraftType: "A320", Rows: 10, Columns: "ABC",
+		Cabins: []model.Cabin{{Class: model.CabinBusiness, FirstRow: 1, LastRow: 2}}}
+
+	pricing := NewPricingEngine(DefaultPricingRules())
+	economy, _ := pricing.Quote(flight, model.CabinEconomy, model.PassengerAdult)
+	business, _ := pricing.Quote(flight, model.CabinBusiness, model.PassengerAdult)
+	changeFee := DefaultFareRules()[model.CabinEconomy].ChangeFee
+	card := &model.PaymentRequest{Method: model.PaymentMethodCard}
+	flightChange := model.ModifyBookingRequest{FlightNumber: "GA404", Seats: []model.SeatChange{{PassengerID: 1, SeatNumber: "5A"}}}
+	upgrade := model.ModifyBookingRequest{Seats: []model.SeatChange{{PassengerID: 1, SeatNumber: "1A"}}}
+
+	tests := []struct {
+		name string
+		paid bool
+		// chargeStatus is the status the provider gives new charges
+		chargeStatus string
+		// existingCharge is the status of a charge the reservation is already being paid through
+		existingCharge string
+		request        model.ModifyBookingRequest
+		// useChargeID sends the change again with the charge of the reservation's previous change
+		useChargeID bool
+		wantErr     error
+		wantApplied bool
+		wantCharge  float64
+		wantRefund  float64
+	}{
+		{
+			name:         "paid booking is charged before the change applies",
+			paid:         true,
+			chargeStatus: model.ChargeSucceeded,
+			request:      model.ModifyBookingRequest{FlightNumber: flightChange.FlightNumber, Seats: flightChange.Seats, Payment: card},
+			wantApplied:  true,
+			wantCharge:   changeFee,
+		},
+		{
+			name:         "paid booking waits for a pending charge",
+			paid:         true,
+			chargeStatus: model.ChargePending,
+			request:      model.ModifyBookingRequest{FlightNumber: flightChange.FlightNumber, Seats: flightChange.Seats, Payment: card},
+			wantCharge:   changeFee,
+		},
+		{
+			name:         "failed charge",
+			paid:         true,
+			chargeStatus: model.ChargeFailed,
+			request:      model.ModifyBookingRequest{FlightNumber: flightChange.FlightNumber, Seats: flightChange.Seats, Payment: card},
+			wantErr:      model.ErrChangeNotPaid,
+			wantCharge:   changeFee,
+		},
+		{
+			name:         "invalid payment method",
+			paid:         true,
+			chargeStatus: model.ChargeSucceeded,
+			request: model.ModifyBookingRequest{FlightNumber: flightChange.FlightNumber, Seats: flightChange.Seats,
+				Payment: &model.PaymentRequest{Method: "cash"}},
+			wantErr: model.ErrInvalidPaymentMethod,
+		},
+		{
+			name:         "charge of the booking cannot pay for a change",
+			paid:         true,
+			chargeStatus: model.ChargeSucceeded,
+			request:      model.ModifyBookingRequest{FlightNumber: flightChange.FlightNumber, Seats: flightChange.Seats, ChargeID: "booking-charge"},
+			wantErr:      model.ErrChargeNotFound,
+		},
+		{
+			name:         "charge of another amount is refunded",
+			paid:         true,
+			chargeStatus: model.ChargeSucceeded,
+			request:      upgrade,
+			useChargeID:  true,
+			wantErr:      model.ErrPaymentAmountMismatch,
+			wantRefund:   changeFee,
+		},
+		{
+			name:           "pending booking gets a charge for the new price",
+			chargeStatus:   model.ChargePending,
+			existingCharge: model.ChargePending,
+			request:        upgrade,
+			wantApplied:    true,
+			wantCharge:     business.Total,
+		},
+		{
+			name:           "pending booking paid meanwhile",
+			chargeStatus:   model.ChargePending,
+			existingCharge: model.ChargeSucceeded,
+			request:        upgrade,
+			wantErr:        model.ErrStatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryFlightRepository(flight, layout)
+			repo.flights[later.FlightNumber] = later
+			payments := newMemoryPaymentProvider(tt.chargeStatus)
+			reservation := model.Reservation{
+				ReservationID: 1,
+				Locator:       "ABC123",
+				FlightNumber:  flight.FlightNumber,
+				Price:         economy.Total,
+				PaymentStatus: model.PaymentUnpaid,
+				Status:        model.ReservationPending,
+				Passengers: []model.ReservationPassenger{
+					{PassengerID: 1, PassengerType: model.PassengerAdult, SeatNumber: "5A", FareClass: model.CabinEconomy, Price: economy.Total},
+				},
+			}
+			if tt.paid {
+				reservation.Status, reservation.PaymentStatus, reservation.PaymentRef =
+					model.ReservationPaid, model.PaymentPaid, "booking-charge"
+				payments.charges["booking-charge"] = model.Charge{ChargeID: "booking-charge", Reference: reservation.Locator,
+					Status: model.ChargeSucceeded, Amount: economy.Total}
+			}
+			if tt.existingCharge != "" {
+				reservation.PaymentRef = "booking-charge"
+				payments.charges["booking-charge"] = model.Charge{ChargeID: "booking-charge", Reference: reservation.Locator,
+					Method: model.PaymentMethodVirtualAccount, Status: tt.existingCharge, Amount: economy.Total}
+			}
+			if tt.useChargeID {
+				payments.charges["change-charge"] = model.Charge{ChargeID: "change-charge", Reference: changeChargeReference(reservation.Locator),
+					Status: model.ChargeSucceeded, Amount: changeFee}
+				tt.request.ChargeID = "change-charge"
+			}
+			repo.reservations[1] = reservation
+			usecase := &FlightUsecase{FlightRepo: repo, Cacher: newMemoryCacher(), Payments: payments, Logger: zap.NewNop()}
+
+			change, err := usecase.ModifyReservation(1, tt.request)
+			if err != tt.wantErr {
+				t.Fatalf("ModifyReservation() error = %v, want %v", err, tt.wantErr)
+			}
+			if applied := len(repo.changes) == 1; applied != tt.wantApplied {
+				t.Errorf("change applied = %v, want %v", applied, tt.wantApplied)
+			}
+
+			var charge *model.Charge
+			for _, created := range payments.charges {
+				if created.ChargeID != "booking-charge" && created.ChargeID != "change-charge" {
+					created := created
+					charge = &created
+				}
+			}
+			switch {
+			case tt.wantCharge == 0 && charge != nil:
+				t.Errorf("ModifyReservation() created a charge of %v", charge.Amount)
+			case tt.wantCharge != 0 && charge == nil:
+				t.Errorf("ModifyReservation() created no charge, want one of %v", tt.wantCharge)
+			case charge != nil && charge.Amount != tt.wantCharge:
+				t.Errorf("charge amount = %v, want %v", charge.Amount, tt.wantCharge)
+			}
+			if charge != nil && tt.wantErr == nil && (change.Charge == nil || change.Charge.ChargeID != charge.ChargeID) &&
+				change.ChargeID != charge.ChargeID {
+				t.Errorf("ModifyReservation() change does not carry charge %s", charge.ChargeID)
+			}
+			if got := payments.refunds["change-charge"]; got != tt.wantRefund {
+				t.Errorf("refunded = %v, want %v", got, tt.wantRefund)
+			}
+
+			if tt.existingCharge == model.ChargePending {
+				if status := payments.charges["booking-charge"].Status; status != model.ChargeVoided {
+					t.Errorf("previous charge status = %s, want %s", status, model.ChargeVoided)
+				}
+				if ref := repo.reservations[1].PaymentRef; charge == nil || ref != charge.ChargeID {
+					t.Errorf("payment reference = %q, want the new charge", ref)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	outboxBatchSize     = 50
+	outboxBaseBackoff   = 2 * time.Second
+	outboxMaxBackoff    = 10 * time.Minute
+	outboxStartDeadline = 10 * time.Second
+)
+
+// DispatchWorkflowOutbox starts the process instances queued by new bookings and records their instance keys.
+// Failed entries are retried with exponential backoff until they succeed. It returns how many were started.
+func (s *FlightUsecase) DispatchWorkflowOutbox() (int, error) {
+	now := time.Now()
+	entries, err := s.FlightRepo.GetPendingOutboxEntries(now, outboxBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	dispatched := 0
+	for _, entry := range entries {
+		// Claiming schedules the next attempt up front, so a crash halfway through is retried as well
+		claimed, err := s.FlightRepo.ClaimOutboxEntry(entry, now.Add(outboxBackoff(entry.Attempts)))
+		if err != nil {
+			return dispatched, err
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := s.dispatchOutboxEntry(entry); err != nil {
+			s.Logger.Error("failed to start process instance", zap.Int("reservation_id", entry.ReservationID),
+				zap.Int("attempts", entry.Attempts+1), zap.Error(err))
+			if err := s.FlightRepo.RecordOutboxError(entry.OutboxID, err.Error()); err != nil {
+				return dispatched, err
+			}
+			continue
+		}
+		dispatched++
+	}
+
+	return dispatched, nil
+}
+
+func (s *FlightUsecase) dispatchOutboxEntry(entry model.WorkflowOutboxEntry) error {
+	reservation, err := s.FlightRepo.GetBookingByID(entry.ReservationID)
+	if err != nil {
+		return err
+	}
+
+	// An earlier attempt got as far as recording the instance, or the reservation closed before it needed one
+	if reservation.InstanceKey == 0 && !isClosedReservation(reservation.Status) {
+		ctx, cancel := context.WithTimeout(context.Background(), outboxStartDeadline)
+		defer cancel()
+
+		instanceKey, err := s.Workflow.StartProcess(ctx, entry.ProcessID, entry.ProcessVersion, entry.Variables)
+		if err != nil {
+			return workflowUnavailable("start process", err)
+		}
+		if err := s.FlightRepo.UpdateInstanceID(entry.ReservationID, instanceKey); err != nil {
+			// An instance the reservation does not know about would run next to the one started by the retry,
+			// or on its own when the reservation was cancelled or expired while it started
+			s.stopProcessInstance(instanceKey)
+			if err != model.ErrStatusConflict {
+				return persistenceFailed("record instance key", err)
+			}
+		}
+	}
+
+	return s.FlightRepo.MarkOutboxDispatched(entry.OutboxID, time.Now())
+}
+
+// outboxBackoff doubles the delay before the next attempt with every failed one
+func outboxBackoff(attempts int) time.Duration {
+	backoff := outboxBaseBackoff
+	for i := 0; i < attempts && backoff < outboxMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > outboxMaxBackoff {
+		return outboxMaxBackoff
+	}
+	return backoff
+}
+
+func isClosedReservation(status string) bool {
+	return status == model.ReservationCancelled || status == model.ReservationExpired
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"booking-engine/internal/workflow"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestOutboxBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 0, want: 2 * time.Second},
+		{attempts: 1, want: 4 * time.Second},
+		{attempts: 5, want: 64 * time.Second},
+		{attempts: 8, want: 512 * time.Second},
+		{attempts: 9, want: outboxMaxBackoff},
+		{attempts: 100, want: outboxMaxBackoff},
+	}
+
+	for _, tt := range tests {
+		if got := outboxBackoff(tt.attempts); got != tt.want {
+			t.Errorf("outboxBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func newOutboxTestUsecase() (*FlightUsecase, *memoryFlightRepository, *workflow.MemoryEngine) {
+	flight := model.Flight{
+		FlightNumber:   "GA101",
+		AircraftType:   "ATR72",
+		DepartureTime:  time.Now().Add(30 * 24 * time.Hour),
+		Price:          1000000,
+		AvailableSeats: 4,
+	}
+	layout := model.SeatLayout{AircraftType: "ATR72", Rows: 2, Columns: "AB"}
+	repo := newMemoryFlightRepository(flight, layout)
+	engine := workflow.NewMemoryEngine()
+
+	s := &FlightUsecase{
+		FlightRepo: repo,
+		PassengerRepo: &memoryPassengerRepository{passengers: map[int]model.Passenger{
+			1: {PassengerID: 1, FirstName: "Budi", LastName: "Santoso", DateOfBirth: "1985-04-12"},
+			2: {PassengerID: 2, FirstName: "Sari", LastName: "Santoso", DateOfBirth: time.Now().AddDate(0, -6, 0).Format(searchDateLayout)},
+		}},
+		Cacher:   newMemoryCacher(),
+		Logger:   zap.NewNop(),
+		Workflow: engine,
+		Process:  model.ProcessDefinition{ProcessID: DefaultProcessID},
+		Location: time.UTC,
+	}
+	return s, repo, engine
+}
+
+func TestBookingThroughOutbox(t *testing.T) {
+	request := model.BookingRequest{
+		FlightNumber: "GA101",
+		Passengers: []model.BookingPassenger{
+			{PassengerID: 1, SeatNumber: "1A"},
+			{PassengerID: 2},
+		},
+	}
+
+	tests := []struct {
+		name string
+		// beforeDispatch runs between booking and the first dispatch
+		beforeDispatch func(t *testing.T, s *FlightUsecase, reservationID int)
+		// closeWhileStarting cancels the reservation after its instance started but before the key is recorded
+		closeWhileStarting bool
+		// afterDispatch runs once the outbox has been dispatched
+		afterDispatch   func(t *testing.T, s *FlightUsecase, reservationID int)
+		wantInstance    bool
+		wantRecordedKey bool
+		wantState       string
+	}{
+		{
+			name:            "instance is started and recorded",
+			wantInstance:    true,
+			wantRecordedKey: true,
+			wantState:       workflow.InstanceActive,
+		},
+		{
+			name: "reservation cancelled before dispatch starts nothing",
+			beforeDispatch: func(t *testing.T, s *FlightUsecase, reservationID int) {
+				if _, err := s.CancelReservation(reservationID); err != nil {
+					t.Fatalf("CancelReservation() error = %v", err)
+				}
+			},
+		},
+		{
+			name:               "reservation cancelled while the instance starts",
+			closeWhileStarting: true,
+			wantInstance:       true,
+			wantState:          workflow.InstanceCancelled,
+		},
+		{
+			name: "reservation cancelled after dispatch stops the instance",
+			afterDispatch: func(t *testing.T, s *FlightUsecase, reservationID int) {
+				if _, err := s.CancelReservation(reservationID); err != nil {
+					t.Fatalf("CancelReservation() error = %v", err)
+				}
+			},
+			wantInstance:    true,
+			wantRecordedKey: true,
+			wantState:       workflow.InstanceCancelled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo, engine := newOutboxTestUsecase()
+
+			booking, err := s.BookFlight(request)
+			if err != nil {
+				t.Fatalf("BookFlight() error = %v", err)
+			}
+			if booking.InstanceKey != 0 || booking.Status != model.ReservationPending {
+				t.Fatalf("BookFlight() = %+v, want a pending reservation without an instance", booking)
+			}
+			if got := booking.Passengers[1].PassengerType; got != model.PassengerInfant {
+				t.Fatalf("second passenger type = %q, want %q from the date of birth", got, model.PassengerInfant)
+			}
+
+			if tt.beforeDispatch != nil {
+				tt.beforeDispatch(t, s, booking.ReservationID)
+			}
+			var startedKey int64
+			repo.beforeUpdateInstance = func(reservationID int, instanceKey int64) {
+				startedKey = instanceKey
+				if tt.closeWhileStarting {
+					if _, err := s.CancelReservation(reservationID); err != nil {
+						t.Fatalf("CancelReservation() error = %v", err)
+					}
+				}
+			}
+
+			dispatched, err := s.DispatchWorkflowOutbox()
+			if err != nil || dispatched != 1 {
+				t.Fatalf("DispatchWorkflowOutbox() = %d, %v, want 1 entry dispatched", dispatched, err)
+			}
+			if tt.afterDispatch != nil {
+				tt.afterDispatch(t, s, booking.ReservationID)
+			}
+			// Dispatched entries are not picked up again
+			if dispatched, err := s.DispatchWorkflowOutbox(); err != nil || dispatched != 0 {
+				t.Fatalf("second DispatchWorkflowOutbox() = %d, %v, want nothing left", dispatched, err)
+			}
+
+			reservation, err := repo.GetBookingByID(booking.ReservationID)
+			if err != nil {
+				t.Fatalf("GetBookingByID() error = %v", err)
+			}
+			if got := reservation.InstanceKey != 0; got != tt.wantRecordedKey {
+				t.Errorf("instance key recorded = %v, want %v", got, tt.wantRecordedKey)
+			}
+			if (startedKey != 0) != tt.wantInstance {
+				t.Fatalf("instance started = %v, want %v", startedKey != 0, tt.wantInstance)
+			}
+			if !tt.wantInstance {
+				return
+			}
+
+			instance, err := engine.Instance(startedKey)
+			if err != nil {
+				t.Fatalf("Instance() error = %v", err)
+			}
+			if instance.State != tt.wantState {
+				t.Errorf("instance state = %s, want %s", instance.State, tt.wantState)
+			}
+			if instance.ProcessID != DefaultProcessID || instance.Variables["locator"] != booking.Locator {
+				t.Errorf("instance = %+v, want %s for locator %s", instance, DefaultProcessID, booking.Locator)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"booking-engine/internal/repository"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// PassengerUsecase handles business logic for passenger profiles
+type PassengerUsecase struct {
+	PassengerRepo repository.PassengerPersister
+}
+
+type PassengerExecutor interface {
+	CreatePassenger(passenger model.Passenger) (model.Passenger, error)
+	GetPassengerByID(passengerID int) (model.Passenger, error)
+	UpdatePassenger(passenger model.Passenger) (model.Passenger, error)
+	DeletePassenger(passengerID int) error
+}
+
+// NewPassengerUsecaseService creates a new instance of the passenger service
+func NewPassengerUsecaseService(passengerUsecase *PassengerUsecase) PassengerExecutor {
+	return passengerUsecase
+}
+
+// CreatePassenger validates and stores a new passenger profile
+func (s *PassengerUsecase) CreatePassenger(passenger model.Passenger) (model.Passenger, error) {
+	passenger, err := normalizePassenger(passenger)
+	if err != nil {
+		return model.Passenger{}, err
+	}
+
+	passengerID, err := s.PassengerRepo.CreatePassenger(passenger)
+	if err != nil {
+		return model.Passenger{}, err
+	}
+
+	return s.PassengerRepo.GetPassengerByID(passengerID)
+}
+
+// GetPassengerByID returns a passenger profile by ID
+func (s *PassengerUsecase) GetPassengerByID(passengerID int) (model.Passenger, error) {
+	return s.PassengerRepo.GetPassengerByID(passengerID)
+}
+
+// UpdatePassenger validates and replaces an existing passenger profile
+func (s *PassengerUsecase) UpdatePassenger(passenger model.Passenger) (model.Passenger, error) {
+	passenger, err := normalizePassenger(passenger)
+	if err != nil {
+		return model.Passenger{}, err
+	}
+	if _, err := s.PassengerRepo.GetPassengerByID(passenger.PassengerID); err != nil {
+		return model.Passenger{}, err
+	}
+
+	if err := s.PassengerRepo.UpdatePassenger(passenger); err != nil {
+		return model.Passenger{}, err
+	}
+
+	return s.PassengerRepo.GetPassengerByID(passenger.PassengerID)
+}
+
+// DeletePassenger deletes a passenger profile that has never been booked
+func (s *PassengerUsecase) DeletePassenger(passengerID int) error {
+	return s.PassengerRepo.DeletePassenger(passengerID)
+}
+
+func normalizePassenger(passenger model.Passenger) (model.Passenger, error) {
+	passenger.FirstName = strings.TrimSpace(passenger.FirstName)
+	passenger.LastName = strings.TrimSpace(passenger.LastName)
+	passenger.Gender = strings.ToLower(strings.TrimSpace(passenger.Gender))
+	passenger.Email = strings.TrimSpace(passenger.Email)
+	passenger.Phone = strings.TrimSpace(passenger.Phone)
+	passenger.Nationality = strings.ToUpper(strings.TrimSpace(passenger.Nationality))
+	passenger.DocumentType = strings.ToLower(strings.TrimSpace(passenger.DocumentType))
+	passenger.DocumentNumber = strings.ToUpper(strings.TrimSpace(passenger.DocumentNumber))
+
+	if passenger.FirstName == "" || passenger.LastName == "" || passenger.DocumentNumber == "" {
+		return passenger, model.ErrInvalidPassenger
+	}
+
+	dateOfBirth, err := time.Parse(searchDateLayout, passenger.DateOfBirth)
+	if err != nil || dateOfBirth.After(time.Now()) {
+		return passenger, model.ErrInvalidPassenger
+	}
+
+	if passenger.Gender != model.GenderMale && passenger.Gender != model.GenderFemale {
+		return passenger, model.ErrInvalidPassenger
+	}
+	if passenger.DocumentType != model.DocumentPassport && passenger.DocumentType != model.DocumentNationalID {
+		return passenger, model.ErrInvalidPassenger
+	}
+	// Nationality is an ISO 3166-1 alpha-2 country code
+	if len(passenger.Nationality) != 2 || strings.Trim(passenger.Nationality, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return passenger, model.ErrInvalidPassenger
+	}
+	if passenger.Email != "" {
+		if _, err := mail.ParseAddress(passenger.Email); err != nil {
+			return passenger, model.ErrInvalidPassenger
+		}
+	}
+
+	return passenger, nil
+}
@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"booking-engine/internal/payment"
+	"context"
+	"math"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// CreatePayment charges a pending reservation through the payment provider and returns the payment instructions.
+// A charge that settles at once marks the reservation paid straight away.
+func (s *FlightUsecase) CreatePayment(id int, request model.PaymentRequest) (model.Charge, error) {
+	request.Method = strings.ToLower(strings.TrimSpace(request.Method))
+	request.Channel = strings.ToLower(strings.TrimSpace(request.Channel))
+	if !payment.ValidMethod(request.Method) {
+		return model.Charge{}, model.ErrInvalidPaymentMethod
+	}
+
+	reservation, err := s.FlightRepo.GetBookingByID(id)
+	if err != nil {
+		return model.Charge{}, err
+	}
+	if reservation.Status != model.ReservationPending || reservation.PaymentStatus == model.PaymentPaid {
+		return model.Charge{}, model.ErrNotPayable
+	}
+
+	charge, err := s.Payments.CreateCharge(context.Background(), model.ChargeRequest{
+		Reference: reservation.Locator,
+		Amount:    reservation.Price,
+		Currency:  model.CurrencyIDR,
+		Method:    request.Method,
+		Channel:   request.Channel,
+	})
+	if err != nil {
+		return model.Charge{}, err
+	}
+	if err := s.FlightRepo.UpdatePaymentReference(id, charge.ChargeID); err != nil {
+		return model.Charge{}, persistenceFailed("record charge", err)
+	}
+
+	if charge.Status == model.ChargeSucceeded {
+		if err := s.settlePayment(reservation, charge.ChargeID, charge.Amount); err != nil {
+			return model.Charge{}, err
+		}
+	}
+
+	return charge, nil
+}
+
+// GetPayment returns the latest charge of a reservation, settling the reservation if the charge has been paid since
+func (s *FlightUsecase) GetPayment(id int) (model.Charge, error) {
+	reservation, err := s.FlightRepo.GetBookingByID(id)
+	if err != nil {
+		return model.Charge{}, err
+	}
+	if reservation.PaymentRef == "" {
+		return model.Charge{}, model.ErrChargeNotFound
+	}
+
+	charge, err := s.Payments.QueryStatus(context.Background(), reservation.PaymentRef)
+	if err != nil {
+		return model.Charge{}, err
+	}
+	if charge.Status != model.ChargeSucceeded || reservation.PaymentStatus != model.PaymentUnpaid {
+		return charge, nil
+	}
+
+	// A closed reservation cannot take the money whatever the amount, settling returns it
+	if reservation.Status == model.ReservationPending && charge.Amount != reservation.Price {
+		return model.Charge{}, model.ErrPaymentAmountMismatch
+	}
+	if err := s.settlePayment(reservation, charge.ChargeID, charge.Amount); err != nil {
+		return model.Charge{}, err
+	}
+
+	return s.Payments.QueryStatus(context.Background(), charge.ChargeID)
+}
+
+// refundPayment returns the refund of a cancelled reservation through the payment provider, from the booking's
+// charge first and then from the charges of paid changes. The reservation is already cancelled at this point,
+// so failures are logged rather than returned.
+func (s *FlightUsecase) refundPayment(reservation model.Reservation, amount float64) {
+	if reservation.PaymentRef == "" {
+		return
+	}
+
+	chargeIDs := []string{reservation.PaymentRef}
+	changes, err := s.FlightRepo.GetReservationChanges(reservation.ReservationID)
+	if err != nil {
+		s.Logger.Error("failed to read charges of changes", zap.Int("reservation_id", reservation.ReservationID), zap.Error(err))
+	}
+	for _, change := range changes {
+		if change.ChargeID != "" {
+			chargeIDs = append(chargeIDs, change.ChargeID)
+		}
+	}
+
+	ctx := context.Background()
+	remaining := amount
+	for _, chargeID := range chargeIDs {
+		if remaining <= 0 {
+			break
+		}
+		charge, err := s.Payments.QueryStatus(ctx, chargeID)
+		if err != nil {
+			s.Logger.Error("failed to read charge for refund", zap.Int("reservation_id", reservation.ReservationID),
+				zap.String("charge_id", chargeID), zap.Error(err))
+			return
+		}
+		refund, err := s.Payments.Refund(ctx, chargeID, math.Min(remaining, charge.Amount))
+		if err != nil {
+			s.Logger.Error("failed to refund payment", zap.Int("reservation_id", reservation.ReservationID),
+				zap.String("charge_id", chargeID), zap.Error(err))
+			return
+		}
+		remaining -= refund.Amount
+	}
+
+	// The refund amount itself is already recorded with the cancellation
+	status := model.PaymentRefunded
+	if amount < reservation.Price {
+		status = model.PaymentPartiallyRefunded
+	}
+	if err := s.FlightRepo.UpdatePaymentStatus(reservation.ReservationID, status); err != nil {
+		s.Logger.Error("failed to record refund", zap.Int("reservation_id", reservation.ReservationID),
+			zap.Float64("amount", amount), zap.Error(err))
+	}
+}
+
+// returnPayment refunds in full a payment the reservation cannot take, because it was closed or already paid through
+// another charge. The payment has been acknowledged already, so failures are logged for a refund by hand.
+func (s *FlightUsecase) returnPayment(reservation model.Reservation, chargeID string, amount float64) {
+	fields := []zap.Field{
+		zap.Int("reservation_id", reservation.ReservationID),
+		zap.String("charge_id", chargeID),
+		zap.Float64("amount", amount),
+	}
+
+	refund, err := s.Payments.Refund(context.Background(), chargeID, amount)
+	if err != nil {
+		s.Logger.Error("failed to return payment", append(fields, zap.Error(err))...)
+		return
+	}
+	s.Logger.Info("payment returned", append(fields, zap.String("refund_id", refund.RefundID))...)
+}
+
+// replaceCharge voids the charge a pending reservation is being paid through once its price changes. The returned
+// function charges the price the reservation ends up with through the same method, whether or not the change went
+// through. A charge that has been paid meanwhile cannot be voided, the change then has to wait for it to settle.
+func (s *FlightUsecase) replaceCharge(reservation model.Reservation) (func() *model.Charge, error) {
+	ctx := context.Background()
+	voided, err := s.Payments.Void(ctx, reservation.PaymentRef)
+	if err != nil {
+		if err == model.ErrChargeNotVoidable {
+			return nil, model.ErrStatusConflict
+		}
+		return nil, err
+	}
+
+	return func() *model.Charge {
+		fields := []zap.Field{
+			zap.Int("reservation_id", reservation.ReservationID),
+			zap.String("voided_charge_id", voided.ChargeID),
+		}
+
+		current, err := s.FlightRepo.GetBookingByID(reservation.ReservationID)
+		if err != nil {
+			s.Logger.Error("failed to replace charge", append(fields, zap.Error(err))...)
+			return nil
+		}
+		charge, err := s.Payments.CreateCharge(ctx, model.ChargeRequest{
+			Reference: current.Locator,
+			Amount:    current.Price,
+			Currency:  voided.Currency,
+			Method:    voided.Method,
+			Channel:   voided.Channel,
+		})
+		if err != nil {
+			s.Logger.Error("failed to replace charge", append(fields, zap.Error(err))...)
+			return nil
+		}
+		if err := s.FlightRepo.UpdatePaymentReference(current.ReservationID, charge.ChargeID); err != nil {
+			s.Logger.Error("failed to record replaced charge", append(fields, zap.String("charge_id", charge.ChargeID), zap.Error(err))...)
+			return nil
+		}
+		if charge.Status == model.ChargeSucceeded {
+			if err := s.settlePayment(current, charge.ChargeID, charge.Amount); err != nil {
+				s.Logger.Error("failed to settle replaced charge", append(fields, zap.String("charge_id", charge.ChargeID), zap.Error(err))...)
+			}
+		}
+		return &charge
+	}, nil
+}
@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"booking-engine/internal/workflow"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const paymentMessageTTL = time.Hour
+
+// HandlePaymentCallback verifies a payment gateway callback, marks the reservation paid and lets the fww-bpm
+// process know. Callbacks are retried by the gateway, so a repeated callback for a paid reservation succeeds again.
+func (s *FlightUsecase) HandlePaymentCallback(payload []byte, signature string) error {
+	if !s.validCallbackSignature(payload, signature) {
+		return model.ErrInvalidSignature
+	}
+
+	var callback model.PaymentCallback
+	if err := json.Unmarshal(payload, &callback); err != nil {
+		return model.ErrInvalidCallback
+	}
+	callback.Status = strings.ToUpper(strings.TrimSpace(callback.Status))
+	if callback.ReservationID <= 0 || callback.TransactionID == "" {
+		return model.ErrInvalidCallback
+	}
+
+	reservation, err := s.FlightRepo.GetBookingByID(callback.ReservationID)
+	if err != nil {
+		return err
+	}
+
+	switch callback.Status {
+	case model.PaymentCallbackPaid:
+	case model.PaymentCallbackFailed:
+		// The customer may still retry within the payment window, expiry takes care of the rest
+		s.Logger.Info("payment failed", zap.Int("reservation_id", reservation.ReservationID),
+			zap.String("transaction_id", callback.TransactionID))
+		return nil
+	default:
+		return model.ErrInvalidCallback
+	}
+
+	// Only the payment a reservation is waiting for has to match its price, any other is already taken or returned
+	if reservation.Status == model.ReservationPending && reservation.PaymentStatus == model.PaymentUnpaid &&
+		callback.Amount != reservation.Price {
+		return model.ErrPaymentAmountMismatch
+	}
+
+	return s.settlePayment(reservation, callback.TransactionID, callback.Amount)
+}
+
+// settlePayment marks a reservation paid unless it already is, and publishes the payment to its process instance.
+// A payment the reservation cannot take is acknowledged and returned, rejecting it would only make the gateway retry.
+func (s *FlightUsecase) settlePayment(reservation model.Reservation, transactionID string, amount float64) error {
+	fields := []zap.Field{
+		zap.Int("reservation_id", reservation.ReservationID),
+		zap.String("transaction_id", transactionID),
+		zap.String("status", reservation.Status),
+	}
+	closed := reservation.Status == model.ReservationCancelled || reservation.Status == model.ReservationExpired
+
+	switch {
+	case reservation.TransactionID == transactionID:
+		// A retry of the payment the reservation was paid with
+		if closed {
+			return nil
+		}
+	case closed:
+		s.Logger.Error("payment received for a closed reservation", fields...)
+		s.returnPayment(reservation, transactionID, amount)
+		return nil
+	case reservation.PaymentStatus != model.PaymentUnpaid:
+		charge, err := s.Payments.QueryStatus(context.Background(), transactionID)
+		if err == nil && charge.Reference == changeChargeReference(reservation.Locator) {
+			// A change is paid for, it applies once it is sent again with its charge
+			return nil
+		}
+		s.Logger.Error("second payment received for a paid reservation",
+			append(fields, zap.String("paid_transaction_id", reservation.TransactionID))...)
+		s.returnPayment(reservation, transactionID, amount)
+		return nil
+	default:
+		if err := checkTransition(reservation.Status, model.ReservationPaid); err != nil {
+			return err
+		}
+		err := s.FlightRepo.MarkReservationPaid(reservation.ReservationID, transactionID, time.Now())
+		if err == model.ErrStatusConflict {
+			// The reservation expired, was cancelled or got paid since it was read, settle against what it is now
+			current, err := s.FlightRepo.GetBookingByID(reservation.ReservationID)
+			if err != nil {
+				return err
+			}
+			if current.Status == reservation.Status {
+				return model.ErrStatusConflict
+			}
+			return s.settlePayment(current, transactionID, amount)
+		}
+		if err != nil {
+			return persistenceFailed("mark reservation paid", err)
+		}
+	}
+
+	// The reservation stays paid when publishing fails, a retry of the payment only publishes again
+	if err := s.publishPaymentReceived(reservation.ReservationID, transactionID); err != nil {
+		return workflowUnavailable("publish payment", err)
+	}
+	return nil
+}
+
+// publishPaymentReceived publishes the payment message correlated to the reservation's process instance.
+// The transaction ID is the message ID so a retried callback does not deliver the payment twice.
+func (s *FlightUsecase) publishPaymentReceived(reservationID int, transactionID string) error {
+	variables := model.BookingVariables{
+		ReservationID: reservationID,
+		StatusPayment: true,
+	}
+
+	return s.Workflow.PublishMessage(context.Background(), workflow.Message{
+		Name:           model.MessagePaymentReceived,
+		CorrelationKey: strconv.Itoa(reservationID),
+		MessageID:      transactionID,
+		TTL:            paymentMessageTTL,
+		Variables:      variables,
+	})
+}
+
+func (s *FlightUsecase) validCallbackSignature(payload []byte, signature string) bool {
+	expected, err := hex.DecodeString(strings.TrimSpace(signature))
+	if err != nil || len(s.PaymentCallbackSecret) == 0 {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, s.PaymentCallbackSecret)
+	mac.Write(payload)
+	return hmac.Equal(expected, mac.Sum(nil))
+}
@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"booking-engine/internal/workflow"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestValidCallbackSignature(t *testing.T) {
+	secret := []byte("callback-secret")
+	payload := []byte(`{"reservation_id":1,"transaction_id":"TX-1","status":"PAID"}`)
+	signature := signCallback(secret, payload)
+
+	tests := []struct {
+		name      string
+		secret    []byte
+		payload   []byte
+		signature string
+		want      bool
+	}{
+		{name: "valid", secret: secret, payload: payload, signature: signature, want: true},
+		{name: "upper case hex with spaces", secret: secret, payload: payload, signature: " " + strings.ToUpper(signature) + "\n", want: true},
+		{name: "tampered payload", secret: secret, payload: []byte(`{"reservation_id":2}`), signature: signature},
+		{name: "other secret", secret: []byte("other-secret"), payload: payload, signature: signature},
+		{name: "no secret configured", payload: payload, signature: signature},
+		{name: "not hex", secret: secret, payload: payload, signature: "not-a-signature"},
+		{name: "empty signature", secret: secret, payload: payload},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &FlightUsecase{PaymentCallbackSecret: tt.secret}
+			if got := s.validCallbackSignature(tt.payload, tt.signature); got != tt.want {
+				t.Errorf("validCallbackSignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Payments the reservation cannot take are charges of 100 at the provider which have to be returned
+func TestHandlePaymentCallback(t *testing.T) {
+	secret := []byte("callback-secret")
+
+	tests := []struct {
+		name        string
+		reservation model.Reservation
+		payload     string
+		signature   string
+		// beforePay changes the reservation between reading it and marking it paid
+		beforePay         func(reservation *model.Reservation)
+		wantErr           error
+		wantStatus        string
+		wantTransactionID string
+		wantRefund        float64
+		wantMessages      int
+	}{
+		{
+			name:              "pending reservation is paid",
+			reservation:       model.Reservation{Status: model.ReservationPending, PaymentStatus: model.PaymentUnpaid, Price: 100},
+			payload:           `{"reservation_id":1,"transaction_id":"TX-1","status":"paid","amount":100}`,
+			wantStatus:        model.ReservationPaid,
+			wantTransactionID: "TX-1",
+			wantMessages:      1,
+		},
+		{
+			name: "retried payment of a paid reservation is published again",
+			reservation: model.Reservation{Status: model.ReservationPaid, PaymentStatus: model.PaymentPaid, Price: 100,
+				TransactionID: "TX-1"},
+			payload:           `{"reservation_id":1,"transaction_id":"TX-1","status":"PAID","amount":100}`,
+			wantStatus:        model.ReservationPaid,
+			wantTransactionID: "TX-1",
+			wantMessages:      1,
+		},
+		{
+			name:        "invalid signature",
+			reservation: model.Reservation{Status: model.ReservationPending, PaymentStatus: model.PaymentUnpaid, Price: 100},
+			payload:     `{"reservation_id":1,"transaction_id":"TX-1","status":"PAID","amount":100}`,
+			signature:   "00",
+			wantErr:     model.ErrInvalidSignature,
+			wantStatus:  model.ReservationPending,
+		},
+		{
+			name:        "unknown status",
+			reservation: model.Reservation{Status: model.ReservationPending, PaymentStatus: model.PaymentUnpaid, Price: 100},
+			payload:     `{"reservation_id":1,"transaction_id":"TX-1","status":"SETTLING","amount":100}`,
+			wantErr:     model.ErrInvalidCallback,
+			wantStatus:  model.ReservationPending,
+		},
+		{
+			name:        "amount mismatch",
+			reservation: model.Reservation{Status: model.ReservationPending, PaymentStatus: model.PaymentUnpaid, Price: 100},
+			payload:     `{"reservation_id":1,"transaction_id":"TX-1","status":"PAID","amount":90}`,
+			wantErr:     model.ErrPaymentAmountMismatch,
+			wantStatus:  model.ReservationPending,
+		},
+		{
+			name:        "failed payment leaves the reservation pending",
+			reservation: model.Reservation{Status: model.ReservationPending, PaymentStatus: model.PaymentUnpaid, Price: 100},
+			payload:     `{"reservation_id":1,"transaction_id":"TX-1","status":"failed","amount":100}`,
+			wantStatus:  model.ReservationPending,
+		},
+		{
+			name:        "payment for a cancelled reservation is acknowledged",
+			reservation: model.Reservation{Status: model.ReservationCancelled, PaymentStatus: model.PaymentUnpaid, Price: 100},
+			payload:     `{"reservation_id":1,"transaction_id":"TX-1","status":"PAID","amount":100}`,
+			wantStatus:  model.ReservationCancelled,
+			wantRefund:  100,
+		},
+		{
+			name: "retried payment of a cancelled reservation is not returned",
+			reservation: model.Reservation{Status: model.ReservationCancelled, PaymentStatus: model.PaymentPaid, Price: 100,
+				TransactionID: "TX-1"},
+			payload:           `{"reservation_id":1,"transaction_id":"TX-1","status":"PAID","amount":100}`,
+			wantStatus:        model.ReservationCancelled,
+			wantTransactionID: "TX-1",
+		},
+		{
+			name:        "payment for a reservation expiring meanwhile is acknowledged",
+			reservation: model.Reservation{Status: model.ReservationPending, PaymentStatus: model.PaymentUnpaid, Price: 100},
+			payload:     `{"reservation_id":1,"transaction_id":"TX-1","status":"PAID","amount":100}`,
+			beforePay: func(reservation *model.Reservation) {
+				reservation.Status = model.ReservationExpired
+			},
+			wantStatus: model.ReservationExpired,
+			wantRefund: 100,
+		},
+		{
+			name: "second transaction for a paid reservation is not recorded",
+			reservation: model.Reservation{Status: model.ReservationPaid, PaymentStatus: model.PaymentPaid, Price: 100,
+				TransactionID: "TX-1"},
+			payload:           `{"reservation_id":1,"transaction_id":"TX-2","status":"PAID","amount":100}`,
+			wantStatus:        model.ReservationPaid,
+			wantTransactionID: "TX-1",
+			wantRefund:        100,
+		},
+		{
+			name: "payment of a change of a paid reservation is kept for the change",
+			reservation: model.Reservation{Status: model.ReservationPaid, PaymentStatus: model.PaymentPaid, Price: 100,
+				Locator: "ABC123", TransactionID: "TX-1"},
+			payload:           `{"reservation_id":1,"transaction_id":"TX-CHANGE","status":"PAID","amount":50}`,
+			wantStatus:        model.ReservationPaid,
+			wantTransactionID: "TX-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryFlightRepository(model.Flight{}, model.SeatLayout{})
+			tt.reservation.ReservationID = 1
+			repo.reservations[1] = tt.reservation
+			if tt.beforePay != nil {
+				repo.beforePay = func(reservationID int) {
+					reservation := repo.reservations[reservationID]
+					tt.beforePay(&reservation)
+					repo.reservations[reservationID] = reservation
+				}
+			}
+
+			signature := tt.signature
+			if signature == "" {
+				signature = signCallback(secret, []byte(tt.payload))
+			}
+			payments := newMemoryPaymentProvider(model.ChargeSucceeded)
+			for _, transactionID := range []string{"TX-1", "TX-2"} {
+				payments.charges[transactionID] = model.Charge{ChargeID: transactionID, Status: model.ChargeSucceeded, Amount: 100}
+			}
+			payments.charges["TX-CHANGE"] = model.Charge{ChargeID: "TX-CHANGE", Reference: changeChargeReference("ABC123"),
+				Status: model.ChargeSucceeded, Amount: 50}
+			engine := workflow.NewMemoryEngine()
+			instanceKey, _ := engine.StartProcess(context.Background(), DefaultProcessID, 0, model.BookingVariables{ReservationID: 1})
+			s := &FlightUsecase{FlightRepo: repo, Payments: payments, Workflow: engine, Logger: zap.NewNop(),
+				PaymentCallbackSecret: secret}
+			if err := s.HandlePaymentCallback([]byte(tt.payload), signature); err != tt.wantErr {
+				t.Fatalf("HandlePaymentCallback() error = %v, want %v", err, tt.wantErr)
+			}
+
+			got := repo.reservations[1]
+			if got.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", got.Status, tt.wantStatus)
+			}
+			if got.TransactionID != tt.wantTransactionID {
+				t.Errorf("transaction ID = %q, want %q", got.TransactionID, tt.wantTransactionID)
+			}
+			if instance, _ := engine.Instance(instanceKey); len(instance.Messages) != tt.wantMessages {
+				t.Errorf("published %d messages, want %d", len(instance.Messages), tt.wantMessages)
+			}
+			if refunded := payments.refunds["TX-1"] + payments.refunds["TX-2"] + payments.refunds["TX-CHANGE"]; refunded != tt.wantRefund {
+				t.Errorf("refunded = %v, want %v", refunded, tt.wantRefund)
+			}
+		})
+	}
+}
+
+func signCallback(secret []byte, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
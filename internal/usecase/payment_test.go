@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestRefundPayment(t *testing.T) {
+	tests := []struct {
+		name        string
+		amount      float64
+		wantRefunds map[string]float64
+		wantStatus  string
+	}{
+		{
+			name:        "full refund spans the booking and change charges",
+			amount:      150,
+			wantRefunds: map[string]float64{"booking-charge": 100, "change-charge": 50},
+			wantStatus:  model.PaymentRefunded,
+		},
+		{
+			name:        "partial refund takes the booking charge first",
+			amount:      80,
+			wantRefunds: map[string]float64{"booking-charge": 80},
+			wantStatus:  model.PaymentPartiallyRefunded,
+		},
+		{
+			name:        "partial refund beyond the booking charge",
+			amount:      120,
+			wantRefunds: map[string]float64{"booking-charge": 100, "change-charge": 20},
+			wantStatus:  model.PaymentPartiallyRefunded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryFlightRepository(model.Flight{}, model.SeatLayout{})
+			reservation := model.Reservation{ReservationID: 1, Price: 150, PaymentStatus: model.PaymentPaid,
+				PaymentRef: "booking-charge", Status: model.ReservationCancelled}
+			repo.reservations[1] = reservation
+			repo.changes = []model.ReservationChange{
+				{ChangeID: 1, ReservationID: 1},
+				{ChangeID: 2, ReservationID: 1, ChargeID: "change-charge", AmountDue: 50},
+			}
+			payments := newMemoryPaymentProvider(model.ChargeSucceeded)
+			payments.charges["booking-charge"] = model.Charge{ChargeID: "booking-charge", Status: model.ChargeSucceeded, Amount: 100}
+			payments.charges["change-charge"] = model.Charge{ChargeID: "change-charge", Status: model.ChargeSucceeded, Amount: 50}
+
+			s := &FlightUsecase{FlightRepo: repo, Payments: payments, Logger: zap.NewNop()}
+			s.refundPayment(reservation, tt.amount)
+
+			for chargeID, want := range tt.wantRefunds {
+				if got := payments.refunds[chargeID]; got != want {
+					t.Errorf("refunded from %s = %v, want %v", chargeID, got, want)
+				}
+			}
+			if len(payments.refunds) != len(tt.wantRefunds) {
+				t.Errorf("refunded from %d charges, want %d", len(payments.refunds), len(tt.wantRefunds))
+			}
+			if got := repo.reservations[1].PaymentStatus; got != tt.wantStatus {
+				t.Errorf("payment status = %s, want %s", got, tt.wantStatus)
+			}
+		})
+	}
+}
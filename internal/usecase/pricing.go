@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"math"
+	"strings"
+)
+
+// PricingRules holds the multipliers and fees used to compute a fare from a flight's base price
+type PricingRules struct {
+	CabinMultipliers     map[string]float64
+	PassengerTypeFactors map[string]float64
+	AirportTax           float64
+	ServiceFee           float64
+	VATRate              float64
+}
+
+// DefaultPricingRules returns the pricing rules used when none are configured
+func DefaultPricingRules() PricingRules {
+	return PricingRules{
+		CabinMultipliers: map[string]float64{
+			model.CabinEconomy:  1,
+			model.CabinBusiness: 2.5,
+		},
+		PassengerTypeFactors: map[string]float64{
+			model.PassengerAdult:  1,
+			model.PassengerChild:  0.75,
+			model.PassengerInfant: 0.1,
+		},
+		AirportTax: 75000,
+		ServiceFee: 15000,
+		VATRate:    0.11,
+	}
+}
+
+// PricingEngine computes fares on the server so client supplied prices are never trusted
+type PricingEngine struct {
+	Rules PricingRules
+}
+
+// NewPricingEngine creates a new instance of the pricing engine
+func NewPricingEngine(rules PricingRules) *PricingEngine {
+	return &PricingEngine{
+		Rules: rules,
+	}
+}
+
+// Quote computes the fare of one passenger of the given type in the given fare class
+func (p *PricingEngine) Quote(flight model.Flight, fareClass string, passengerType string) (model.Fare, error) {
+	multiplier, ok := p.Rules.CabinMultipliers[fareClass]
+	if !ok {
+		return model.Fare{}, model.ErrInvalidFareClass
+	}
+	factor, ok := p.Rules.PassengerTypeFactors[passengerType]
+	if !ok {
+		return model.Fare{}, model.ErrInvalidPassengerType
+	}
+
+	fare := model.Fare{
+		FlightNumber:  flight.FlightNumber,
+		FareClass:     fareClass,
+		PassengerType: passengerType,
+		BaseFare:      roundRupiah(flight.Price * multiplier * factor),
+		ServiceFee:    p.Rules.ServiceFee,
+		Currency:      model.CurrencyIDR,
+	}
+	// Infants travel on an adult's lap and are exempt from the airport tax
+	if passengerType != model.PassengerInfant {
+		fare.AirportTax = p.Rules.AirportTax
+	}
+	fare.VAT = roundRupiah((fare.BaseFare + fare.ServiceFee) * p.Rules.VATRate)
+	fare.Total = fare.BaseFare + fare.AirportTax + fare.ServiceFee + fare.VAT
+
+	return fare, nil
+}
+
+// GetFare returns the current fare of a flight for a fare class and passenger type
+func (s *FlightUsecase) GetFare(flightID string, request model.FareRequest) (model.Fare, error) {
+	flight, err := s.GetFlightByID(flightID)
+	if err != nil {
+		return model.Fare{}, err
+	}
+
+	return s.pricing().Quote(*flight, normalizeFareClass(request.FareClass), normalizePassengerType(request.PassengerType))
+}
+
+// pricePassenger computes the fare of one booked passenger, rejecting client supplied prices that differ from it.
+// Seated passengers fly in the cabin of their seat, a redeemed quote must match that cabin and the passenger type.
+func (s *FlightUsecase) pricePassenger(flight model.Flight, seat *model.Seat, passenger model.BookingPassenger, quote *model.QuoteClaims) (model.Fare, error) {
+	fareClass := normalizeFareClass(passenger.FareClass)
+	if seat != nil {
+		if passenger.FareClass == "" {
+			fareClass = seat.Cabin
+		}
+		if fareClass != seat.Cabin {
+			return model.Fare{}, model.ErrInvalidFareClass
+		}
+	}
+	passengerType := normalizePassengerType(passenger.PassengerType)
+
+	var (
+		fare model.Fare
+		err  error
+	)
+	// A valid quote guarantees its price even if the flight was repriced since it was issued
+	if quote != nil {
+		quoted, ok := quote.FareFor(passengerType)
+		if !ok || quoted.FareClass != fareClass {
+			return model.Fare{}, model.ErrQuoteMismatch
+		}
+		fare = quoted
+	} else {
+		fare, err = s.pricing().Quote(flight, fareClass, passengerType)
+	}
+	if err != nil {
+		return model.Fare{}, err
+	}
+	if passenger.Price != 0 && passenger.Price != fare.Total {
+		return model.Fare{}, model.ErrPriceMismatch
+	}
+
+	return fare, nil
+}
+
+func (s *FlightUsecase) pricing() *PricingEngine {
+	if s.Pricing == nil {
+		return NewPricingEngine(DefaultPricingRules())
+	}
+	return s.Pricing
+}
+
+func normalizeFareClass(fareClass string) string {
+	fareClass = strings.ToLower(strings.TrimSpace(fareClass))
+	if fareClass == "" {
+		return model.CabinEconomy
+	}
+	return fareClass
+}
+
+func normalizePassengerType(passengerType string) string {
+	passengerType = strings.ToLower(strings.TrimSpace(passengerType))
+	if passengerType == "" {
+		return model.PassengerAdult
+	}
+	return passengerType
+}
+
+func roundRupiah(amount float64) float64 {
+	return math.Round(amount)
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"testing"
+)
+
+func TestPricingEngineQuote(t *testing.T) {
+	engine := NewPricingEngine(DefaultPricingRules())
+	flight := model.Flight{FlightNumber: "GA101", Price: 1000000}
+
+	tests := []struct {
+		name          string
+		fareClass     string
+		passengerType string
+		want          model.Fare
+		wantErr       error
+	}{
+		{
+			name:          "economy adult",
+			fareClass:     model.CabinEconomy,
+			passengerType: model.PassengerAdult,
+			want:          model.Fare{BaseFare: 1000000, AirportTax: 75000, ServiceFee: 15000, VAT: 111650, Total: 1201650},
+		},
+		{
+			name:          "business adult",
+			fareClass:     model.CabinBusiness,
+			passengerType: model.PassengerAdult,
+			want:          model.Fare{BaseFare: 2500000, AirportTax: 75000, ServiceFee: 15000, VAT: 276650, Total: 2866650},
+		},
+		{
+			name:          "economy child",
+			fareClass:     model.CabinEconomy,
+			passengerType: model.PassengerChild,
+			want:          model.Fare{BaseFare: 750000, AirportTax: 75000, ServiceFee: 15000, VAT: 84150, Total: 924150},
+		},
+		{
+			name:          "economy infant pays no airport tax",
+			fareClass:     model.CabinEconomy,
+			passengerType: model.PassengerInfant,
+			want:          model.Fare{BaseFare: 100000, ServiceFee: 15000, VAT: 12650, Total: 127650},
+		},
+		{name: "unknown fare class", fareClass: "first", passengerType: model.PassengerAdult, wantErr: model.ErrInvalidFareClass},
+		{name: "unknown passenger type", fareClass: model.CabinEconomy, passengerType: "senior", wantErr: model.ErrInvalidPassengerType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := engine.Quote(flight, tt.fareClass, tt.passengerType)
+			if err != tt.wantErr {
+				t.Fatalf("Quote() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			tt.want.FlightNumber = flight.FlightNumber
+			tt.want.FareClass = tt.fareClass
+			tt.want.PassengerType = tt.passengerType
+			tt.want.Currency = model.CurrencyIDR
+			if got != tt.want {
+				t.Errorf("Quote() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
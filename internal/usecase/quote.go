@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+const DefaultQuoteTTL = 15 * time.Minute
+
+// QuoteSigner signs and verifies fare quote tokens with HMAC-SHA256
+type QuoteSigner struct {
+	Key []byte
+	TTL time.Duration
+}
+
+// NewQuoteSigner creates a new instance of the quote signer
+func NewQuoteSigner(key []byte, ttl time.Duration) *QuoteSigner {
+	if ttl <= 0 {
+		ttl = DefaultQuoteTTL
+	}
+	return &QuoteSigner{
+		Key: key,
+		TTL: ttl,
+	}
+}
+
+// Sign encodes the claims as "<payload>.<signature>", both base64url encoded
+func (q *QuoteSigner) Sign(claims model.QuoteClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(q.signature(encoded)), nil
+}
+
+// Verify checks the token signature and expiry and returns its claims
+func (q *QuoteSigner) Verify(token string, now time.Time) (model.QuoteClaims, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return model.QuoteClaims{}, model.ErrQuoteInvalid
+	}
+
+	expected, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, q.signature(encoded)) {
+		return model.QuoteClaims{}, model.ErrQuoteInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return model.QuoteClaims{}, model.ErrQuoteInvalid
+	}
+	var claims model.QuoteClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return model.QuoteClaims{}, model.ErrQuoteInvalid
+	}
+	if !now.Before(claims.ExpiresAt) {
+		return model.QuoteClaims{}, model.ErrQuoteExpired
+	}
+
+	return claims, nil
+}
+
+func (q *QuoteSigner) signature(encoded string) []byte {
+	mac := hmac.New(sha256.New, q.Key)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
+
+// CreateQuote prices a flight for a group of passengers and returns a signed token guaranteeing that price
+func (s *FlightUsecase) CreateQuote(request model.QuoteRequest) (model.Quote, error) {
+	breakdown, err := normalizeQuoteBreakdown(request)
+	if err != nil {
+		return model.Quote{}, err
+	}
+
+	flight, err := s.FlightRepo.GetFlightByNumber(strings.ToUpper(strings.TrimSpace(request.FlightNumber)))
+	if err != nil {
+		return model.Quote{}, err
+	}
+
+	claims := model.QuoteClaims{
+		FlightNumber: flight.FlightNumber,
+		ExpiresAt:    time.Now().Add(s.Quotes.TTL).UTC().Truncate(time.Second),
+	}
+	seated := 0
+	fareClass := normalizeFareClass(request.FareClass)
+	for _, passengers := range breakdown {
+		fare, err := s.pricing().Quote(flight, fareClass, passengers.PassengerType)
+		if err != nil {
+			return model.Quote{}, err
+		}
+		claims.Fares = append(claims.Fares, model.QuotedFare{Fare: fare, Count: passengers.Count})
+		claims.Passengers += passengers.Count
+		claims.Total += fare.Total * float64(passengers.Count)
+		// Infants can travel on the lap of an adult
+		if passengers.PassengerType != model.PassengerInfant {
+			seated += passengers.Count
+		}
+	}
+	if flight.AvailableSeats < seated {
+		return model.Quote{}, model.ErrFlightSoldOut
+	}
+
+	token, err := s.Quotes.Sign(claims)
+	if err != nil {
+		return model.Quote{}, err
+	}
+
+	return model.Quote{
+		QuoteClaims: claims,
+		Token:       token,
+	}, nil
+}
+
+// normalizeQuoteBreakdown returns the passenger count of every passenger type in the request, one entry per type
+func normalizeQuoteBreakdown(request model.QuoteRequest) ([]model.QuotePassenger, error) {
+	breakdown := request.Breakdown
+	if len(breakdown) == 0 {
+		if request.Passengers == 0 {
+			request.Passengers = 1
+		}
+		breakdown = []model.QuotePassenger{{PassengerType: request.PassengerType, Count: request.Passengers}}
+	} else if request.PassengerType != "" || request.Passengers != 0 {
+		return nil, model.ErrInvalidQuote
+	}
+
+	result := make([]model.QuotePassenger, 0, len(breakdown))
+	seen := map[string]bool{}
+	total := 0
+	for _, passengers := range breakdown {
+		passengers.PassengerType = normalizePassengerType(passengers.PassengerType)
+		if passengers.Count <= 0 || seen[passengers.PassengerType] {
+			return nil, model.ErrInvalidQuote
+		}
+		seen[passengers.PassengerType] = true
+		total += passengers.Count
+		result = append(result, passengers)
+	}
+	if total > MaxPassengersPerBooking {
+		return nil, model.ErrInvalidQuote
+	}
+
+	return result, nil
+}
+
+// redeemQuote verifies a quote token and checks that it was issued for this flight and the passenger types of the booking
+func (s *FlightUsecase) redeemQuote(token string, flight model.Flight, passengers []model.BookingPassenger) (*model.QuoteClaims, error) {
+	claims, err := s.Quotes.Verify(token, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if claims.FlightNumber != flight.FlightNumber || claims.Passengers != len(passengers) {
+		return nil, model.ErrQuoteMismatch
+	}
+
+	counts := map[string]int{}
+	for _, passenger := range passengers {
+		counts[passenger.PassengerType]++
+	}
+	for _, fare := range claims.Fares {
+		if counts[fare.PassengerType] != fare.Count {
+			return nil, model.ErrQuoteMismatch
+		}
+	}
+
+	return &claims, nil
+}
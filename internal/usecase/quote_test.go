@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQuoteSignerVerify(t *testing.T) {
+	now := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	signer := NewQuoteSigner([]byte("quote-key"), 0)
+	claims := model.QuoteClaims{
+		FlightNumber: "GA101",
+		Fares: []model.QuotedFare{
+			{Fare: model.Fare{FareClass: model.CabinEconomy, PassengerType: model.PassengerAdult, Total: 1000000}, Count: 2},
+			{Fare: model.Fare{FareClass: model.CabinEconomy, PassengerType: model.PassengerInfant, Total: 100000}, Count: 1},
+		},
+		Passengers: 3,
+		Total:      2100000,
+		ExpiresAt:  now.Add(signer.TTL),
+	}
+	token, err := signer.Sign(claims)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	payload, signature, _ := strings.Cut(token, ".")
+
+	tests := []struct {
+		name    string
+		signer  *QuoteSigner
+		token   string
+		now     time.Time
+		wantErr error
+	}{
+		{name: "valid", signer: signer, token: token, now: now},
+		{name: "expired", signer: signer, token: token, now: claims.ExpiresAt, wantErr: model.ErrQuoteExpired},
+		{name: "other key", signer: NewQuoteSigner([]byte("other-key"), 0), token: token, now: now, wantErr: model.ErrQuoteInvalid},
+		{name: "no signature", signer: signer, token: payload, now: now, wantErr: model.ErrQuoteInvalid},
+		{name: "tampered payload", signer: signer, token: "e30." + signature, now: now, wantErr: model.ErrQuoteInvalid},
+		{name: "malformed signature", signer: signer, token: payload + ".!!", now: now, wantErr: model.ErrQuoteInvalid},
+		{name: "empty", signer: signer, token: "", now: now, wantErr: model.ErrQuoteInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.signer.Verify(tt.token, tt.now)
+			if err != tt.wantErr {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.FlightNumber != claims.FlightNumber || got.Total != claims.Total || len(got.Fares) != len(claims.Fares) {
+				t.Errorf("Verify() = %+v, want %+v", got, claims)
+			}
+			if fare, ok := got.FareFor(model.PassengerInfant); !ok || fare.Total != 100000 {
+				t.Errorf("FareFor(infant) = %+v, %v", fare, ok)
+			}
+		})
+	}
+}
+
+func TestNormalizeQuoteBreakdown(t *testing.T) {
+	tests := []struct {
+		name    string
+		request model.QuoteRequest
+		want    map[string]int
+		wantErr error
+	}{
+		{
+			name:    "single passenger by default",
+			request: model.QuoteRequest{},
+			want:    map[string]int{model.PassengerAdult: 1},
+		},
+		{
+			name:    "single type group",
+			request: model.QuoteRequest{PassengerType: "Child", Passengers: 3},
+			want:    map[string]int{model.PassengerChild: 3},
+		},
+		{
+			name: "mixed group",
+			request: model.QuoteRequest{Breakdown: []model.QuotePassenger{
+				{PassengerType: model.PassengerAdult, Count: 2},
+				{PassengerType: model.PassengerInfant, Count: 1},
+			}},
+			want: map[string]int{model.PassengerAdult: 2, model.PassengerInfant: 1},
+		},
+		{
+			name: "breakdown and passengers together",
+			request: model.QuoteRequest{Passengers: 3, Breakdown: []model.QuotePassenger{
+				{PassengerType: model.PassengerAdult, Count: 3},
+			}},
+			wantErr: model.ErrInvalidQuote,
+		},
+		{
+			name: "repeated type",
+			request: model.QuoteRequest{Breakdown: []model.QuotePassenger{
+				{PassengerType: model.PassengerAdult, Count: 1},
+				{PassengerType: "ADULT", Count: 1},
+			}},
+			wantErr: model.ErrInvalidQuote,
+		},
+		{
+			name:    "negative count",
+			request: model.QuoteRequest{Passengers: -1},
+			wantErr: model.ErrInvalidQuote,
+		},
+		{
+			name:    "too many passengers",
+			request: model.QuoteRequest{Passengers: MaxPassengersPerBooking + 1},
+			wantErr: model.ErrInvalidQuote,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeQuoteBreakdown(tt.request)
+			if err != tt.wantErr {
+				t.Fatalf("normalizeQuoteBreakdown() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("normalizeQuoteBreakdown() = %+v, want %v", got, tt.want)
+			}
+			for _, passengers := range got {
+				if tt.want[passengers.PassengerType] != passengers.Count {
+					t.Errorf("normalizeQuoteBreakdown() = %+v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
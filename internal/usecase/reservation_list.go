@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	reservationsDefaultLimit = 20
+	reservationsMaxLimit     = 100
+)
+
+// GetAllReservations returns a page of reservations matching the filters of the request
+func (s *FlightUsecase) GetAllReservations(request model.ReservationListRequest) (model.ReservationPage, error) {
+	filter, err := buildReservationFilter(request, s.location())
+	if err != nil {
+		return model.ReservationPage{}, err
+	}
+
+	// One extra row tells whether there is a next page without a second query
+	pageSize := filter.Limit
+	filter.Limit++
+	reservations, total, err := s.FlightRepo.GetAllReservations(filter)
+	if err != nil {
+		return model.ReservationPage{}, err
+	}
+
+	page := model.ReservationPage{
+		Data:  reservations,
+		Limit: pageSize,
+		Total: total,
+	}
+	if len(reservations) > pageSize {
+		page.Data = reservations[:pageSize]
+		page.NextCursor = encodeCursor(page.Data[pageSize-1].ReservationID)
+	}
+
+	return page, nil
+}
+
+func buildReservationFilter(request model.ReservationListRequest, loc *time.Location) (model.ReservationFilter, error) {
+	filter := model.ReservationFilter{
+		FlightNumber: strings.ToUpper(strings.TrimSpace(request.FlightNumber)),
+		PassengerID:  request.PassengerID,
+		Status:       strings.ToUpper(strings.TrimSpace(request.Status)),
+		Limit:        request.Limit,
+		SortDesc:     true,
+	}
+
+	if request.Limit < 0 || request.PassengerID < 0 {
+		return filter, model.ErrInvalidListRequest
+	}
+	if filter.Limit == 0 {
+		filter.Limit = reservationsDefaultLimit
+	}
+	if filter.Limit > reservationsMaxLimit {
+		filter.Limit = reservationsMaxLimit
+	}
+
+	if filter.Status != "" && !isReservationStatus(filter.Status) {
+		return filter, model.ErrInvalidListRequest
+	}
+
+	switch strings.ToLower(request.Order) {
+	case "", "desc":
+	case "asc":
+		filter.SortDesc = false
+	default:
+		return filter, model.ErrInvalidListRequest
+	}
+
+	if request.Cursor != "" {
+		afterID, err := decodeCursor(request.Cursor)
+		if err != nil {
+			return filter, model.ErrInvalidListRequest
+		}
+		filter.AfterID = afterID
+	}
+
+	var err error
+	if request.CreatedFrom != "" {
+		if filter.CreatedFrom, _, err = parseDateOrTime(request.CreatedFrom, loc); err != nil {
+			return filter, model.ErrInvalidListRequest
+		}
+	}
+	if request.CreatedTo != "" {
+		createdTo, dateOnly, err := parseDateOrTime(request.CreatedTo, loc)
+		if err != nil {
+			return filter, model.ErrInvalidListRequest
+		}
+		// A plain date includes the whole day
+		if dateOnly {
+			createdTo = createdTo.AddDate(0, 0, 1)
+		}
+		filter.CreatedTo = createdTo
+	}
+
+	return filter, nil
+}
+
+// parseDateOrTime accepts either a YYYY-MM-DD date, taken as local midnight in loc, or an RFC 3339 timestamp
+func parseDateOrTime(value string, loc *time.Location) (t time.Time, dateOnly bool, err error) {
+	if t, err := time.ParseInLocation(searchDateLayout, value, loc); err == nil {
+		return t, true, nil
+	}
+	t, err = time.Parse(time.RFC3339, value)
+	return t, false, err
+}
+
+// encodeCursor turns a reservation ID into an opaque page cursor
+func encodeCursor(reservationID int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(reservationID)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(string(raw))
+}
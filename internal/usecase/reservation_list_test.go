@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"testing"
+	"time"
+)
+
+func TestCursor(t *testing.T) {
+	tests := []struct {
+		name    string
+		cursor  string
+		want    int
+		wantErr bool
+	}{
+		{name: "encoded id", cursor: encodeCursor(42), want: 42},
+		{name: "large id", cursor: encodeCursor(1 << 30), want: 1 << 30},
+		{name: "not base64", cursor: "!!", wantErr: true},
+		{name: "not a number", cursor: "YWJj", wantErr: true},
+		{name: "padded base64", cursor: "NDI=", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeCursor(tt.cursor)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeCursor(%q) error = %v, want error %v", tt.cursor, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("decodeCursor(%q) = %d, want %d", tt.cursor, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildReservationFilter(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+
+	tests := []struct {
+		name     string
+		request  model.ReservationListRequest
+		wantFrom time.Time
+		wantTo   time.Time
+		wantErr  error
+	}{
+		{
+			name:     "dates cover whole local days",
+			request:  model.ReservationListRequest{CreatedFrom: "2026-03-01", CreatedTo: "2026-03-02"},
+			wantFrom: time.Date(2026, 2, 28, 17, 0, 0, 0, time.UTC),
+			wantTo:   time.Date(2026, 3, 2, 17, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "timestamps are taken as given",
+			request:  model.ReservationListRequest{CreatedFrom: "2026-03-01T08:00:00Z", CreatedTo: "2026-03-01T10:00:00+07:00"},
+			wantFrom: time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC),
+			wantTo:   time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC),
+		},
+		{name: "malformed date", request: model.ReservationListRequest{CreatedFrom: "01-03-2026"}, wantErr: model.ErrInvalidListRequest},
+		{name: "unknown status", request: model.ReservationListRequest{Status: "lost"}, wantErr: model.ErrInvalidListRequest},
+		{name: "unknown order", request: model.ReservationListRequest{Order: "newest"}, wantErr: model.ErrInvalidListRequest},
+		{name: "negative limit", request: model.ReservationListRequest{Limit: -1}, wantErr: model.ErrInvalidListRequest},
+		{name: "malformed cursor", request: model.ReservationListRequest{Cursor: "!!"}, wantErr: model.ErrInvalidListRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := buildReservationFilter(tt.request, jakarta)
+			if err != tt.wantErr {
+				t.Fatalf("buildReservationFilter() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !filter.CreatedFrom.Equal(tt.wantFrom) || !filter.CreatedTo.Equal(tt.wantTo) {
+				t.Errorf("buildReservationFilter() created = %v to %v, want %v to %v",
+					filter.CreatedFrom, filter.CreatedTo, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"strings"
+	"time"
+)
+
+// reservationTransitions lists the statuses a reservation may move to from each status.
+// Cancelled and expired reservations are final.
+var reservationTransitions = map[string][]string{
+	model.ReservationPending:  {model.ReservationPaid, model.ReservationCancelled, model.ReservationExpired},
+	model.ReservationPaid:     {model.ReservationTicketed, model.ReservationCancelled},
+	model.ReservationTicketed: {model.ReservationCancelled},
+}
+
+// TransitionReservation moves a reservation to a new status when the state machine allows it. Only ticketing is done
+// this way, paying, cancelling and expiring have their own operations that also settle money, seats and the process.
+func (s *FlightUsecase) TransitionReservation(id int, status string) (model.Reservation, error) {
+	status = strings.ToUpper(strings.TrimSpace(status))
+	if !isReservationStatus(status) {
+		return model.Reservation{}, model.ErrUnknownStatus
+	}
+	if status != model.ReservationTicketed {
+		return model.Reservation{}, model.ErrStatusNotSettable
+	}
+
+	reservation, err := s.FlightRepo.GetBookingByID(id)
+	if err != nil {
+		return model.Reservation{}, err
+	}
+	if err := checkTransition(reservation.Status, status); err != nil {
+		return model.Reservation{}, err
+	}
+
+	if err := s.FlightRepo.UpdateReservationStatus(id, reservation.Status, status, time.Now()); err != nil {
+		return model.Reservation{}, err
+	}
+
+	return s.FlightRepo.GetBookingByID(id)
+}
+
+// checkTransition returns an InvalidTransitionError when the state machine does not allow from -> to
+func checkTransition(from string, to string) error {
+	if !isReservationStatus(to) {
+		return model.ErrUnknownStatus
+	}
+
+	for _, allowed := range reservationTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+
+	return &model.InvalidTransitionError{From: from, To: to}
+}
+
+func isReservationStatus(status string) bool {
+	switch status {
+	case model.ReservationPending, model.ReservationPaid, model.ReservationTicketed,
+		model.ReservationCancelled, model.ReservationExpired:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"errors"
+	"testing"
+)
+
+func TestCheckTransition(t *testing.T) {
+	tests := []struct {
+		from    string
+		to      string
+		wantErr error
+	}{
+		{from: model.ReservationPending, to: model.ReservationPaid},
+		{from: model.ReservationPending, to: model.ReservationCancelled},
+		{from: model.ReservationPending, to: model.ReservationExpired},
+		{from: model.ReservationPending, to: model.ReservationTicketed, wantErr: model.ErrInvalidTransition},
+		{from: model.ReservationPending, to: model.ReservationPending, wantErr: model.ErrInvalidTransition},
+		{from: model.ReservationPaid, to: model.ReservationTicketed},
+		{from: model.ReservationPaid, to: model.ReservationCancelled},
+		{from: model.ReservationPaid, to: model.ReservationExpired, wantErr: model.ErrInvalidTransition},
+		{from: model.ReservationTicketed, to: model.ReservationCancelled},
+		{from: model.ReservationTicketed, to: model.ReservationPaid, wantErr: model.ErrInvalidTransition},
+		{from: model.ReservationCancelled, to: model.ReservationPending, wantErr: model.ErrInvalidTransition},
+		{from: model.ReservationCancelled, to: model.ReservationPaid, wantErr: model.ErrInvalidTransition},
+		{from: model.ReservationExpired, to: model.ReservationPaid, wantErr: model.ErrInvalidTransition},
+		{from: model.ReservationPending, to: "REFUNDED", wantErr: model.ErrUnknownStatus},
+		{from: "", to: model.ReservationPaid, wantErr: model.ErrInvalidTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			err := checkTransition(tt.from, tt.to)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("checkTransition() error = %v, want %v", err, tt.wantErr)
+			}
+
+			var transitionErr *model.InvalidTransitionError
+			if errors.As(err, &transitionErr) && (transitionErr.From != tt.from || transitionErr.To != tt.to) {
+				t.Errorf("checkTransition() error = %+v, want from %s to %s", transitionErr, tt.from, tt.to)
+			}
+		})
+	}
+}
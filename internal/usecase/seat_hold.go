@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"booking-engine/config"
+	"booking-engine/internal/model"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultSeatHoldTTL  = 10 * time.Minute
+	seatHoldMaxAttempts = 3
+)
+
+// HoldSeat temporarily reserves a free seat for the holder, renewing the hold if the holder already owns it
+func (s *FlightUsecase) HoldSeat(flightID string, seatNumber string, holderID string) (model.SeatHold, error) {
+	holderID = strings.TrimSpace(holderID)
+	if holderID == "" {
+		return model.SeatHold{}, model.ErrInvalidHolder
+	}
+
+	flight, err := s.GetFlightByID(flightID)
+	if err != nil {
+		return model.SeatHold{}, err
+	}
+	seatNumber = normalizeSeatNumber(seatNumber)
+	if _, err := s.findSeat(*flight, seatNumber); err != nil {
+		return model.SeatHold{}, err
+	}
+	if err := s.ensureSeatFree(flight.FlightNumber, seatNumber); err != nil {
+		return model.SeatHold{}, err
+	}
+
+	ctx := context.Background()
+	key := seatHoldKey(flight.FlightNumber, seatNumber)
+	ttl := s.seatHoldTTL()
+
+	if err := s.acquireSeatHold(ctx, key, holderID, ttl); err != nil {
+		return model.SeatHold{}, err
+	}
+
+	return model.SeatHold{
+		FlightNumber: flight.FlightNumber,
+		SeatNumber:   seatNumber,
+		HolderID:     holderID,
+		ExpiresAt:    time.Now().Add(ttl),
+	}, nil
+}
+
+// ReleaseSeatHold removes the holder's hold on a seat
+func (s *FlightUsecase) ReleaseSeatHold(flightID string, seatNumber string, holderID string) error {
+	holderID = strings.TrimSpace(holderID)
+	if holderID == "" {
+		return model.ErrInvalidHolder
+	}
+
+	flight, err := s.GetFlightByID(flightID)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	key := seatHoldKey(flight.FlightNumber, normalizeSeatNumber(seatNumber))
+	deleted, err := s.Cacher.DelIfEqual(ctx, key, holderID)
+	if err != nil {
+		return err
+	}
+	if deleted {
+		return nil
+	}
+
+	// Nothing was removed, tell apart a missing hold from one owned by someone else
+	if _, err := s.Cacher.Get(ctx, key); err != nil {
+		if err == config.ErrCacheMiss {
+			return model.ErrSeatHoldNotFound
+		}
+		return err
+	}
+	return model.ErrSeatHeld
+}
+
+// acquireSeatHold takes the hold for the holder or renews it when the holder already owns it.
+// Both steps are atomic in the cache, a hold that expires in between is simply taken again.
+func (s *FlightUsecase) acquireSeatHold(ctx context.Context, key string, holderID string, ttl time.Duration) error {
+	for attempt := 0; attempt < seatHoldMaxAttempts; attempt++ {
+		ok, err := s.Cacher.SetNX(ctx, key, holderID, ttl)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+
+		renewed, err := s.Cacher.ExpireIfEqual(ctx, key, holderID, ttl)
+		if err != nil {
+			return err
+		}
+		if renewed {
+			return nil
+		}
+
+		// The hold belongs to someone else unless it expired since SetNX
+		owner, err := s.Cacher.Get(ctx, key)
+		if err == config.ErrCacheMiss {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if owner != holderID {
+			return model.ErrSeatHeld
+		}
+	}
+
+	return model.ErrSeatHeld
+}
+
+// checkSeatHold allows booking a seat only when it is unheld or held by the same holder
+func (s *FlightUsecase) checkSeatHold(flightNumber string, seatNumber string, holderID string) error {
+	owner, err := s.Cacher.Get(context.Background(), seatHoldKey(flightNumber, seatNumber))
+	if err != nil {
+		if err == config.ErrCacheMiss {
+			return nil
+		}
+		return err
+	}
+	if owner != strings.TrimSpace(holderID) {
+		return model.ErrSeatHeld
+	}
+
+	return nil
+}
+
+// ensureSeatFree rejects seats that have already been sold on the flight
+func (s *FlightUsecase) ensureSeatFree(flightNumber string, seatNumber string) error {
+	occupied, err := s.FlightRepo.GetOccupiedSeats(flightNumber)
+	if err != nil {
+		return err
+	}
+	for _, seat := range occupied {
+		if seat == seatNumber {
+			return model.ErrSeatTaken
+		}
+	}
+
+	return nil
+}
+
+func (s *FlightUsecase) seatHoldTTL() time.Duration {
+	if s.SeatHoldTTL <= 0 {
+		return DefaultSeatHoldTTL
+	}
+	return s.SeatHoldTTL
+}
+
+func seatHoldKey(flightNumber string, seatNumber string) string {
+	return fmt.Sprintf("seat-hold:%s:%s", flightNumber, seatNumber)
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"context"
+	"testing"
+	"time"
+)
+
+func newSeatHoldUsecase() (*FlightUsecase, *memoryFlightRepository, *memoryCacher) {
+	repo := newMemoryFlightRepository(
+		model.Flight{FlightID: 1, FlightNumber: "GA402", AircraftType: "ATR72", AvailableSeats: 6},
+		model.SeatLayout{AircraftType: "ATR72", Rows: 3, Columns: "AB", BlockedSeats: []string{"3B"}},
+	)
+	cacher := newMemoryCacher()
+	return &FlightUsecase{FlightRepo: repo, Cacher: cacher}, repo, cacher
+}
+
+func TestAcquireSeatHold(t *testing.T) {
+	const key = "seat-hold:GA402:1A"
+
+	tests := []struct {
+		name    string
+		owner   string
+		setup   func(cacher *memoryCacher)
+		wantErr error
+	}{
+		{name: "free seat"},
+		{name: "renewed by the same holder", owner: "alice"},
+		{name: "held by another holder", owner: "bob", wantErr: model.ErrSeatHeld},
+		{
+			name:  "hold expires between SetNX and renewal",
+			owner: "bob",
+			setup: func(cacher *memoryCacher) {
+				cacher.beforeCompare = func(key string) {
+					cacher.beforeCompare = nil
+					cacher.expire(key)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase, _, cacher := newSeatHoldUsecase()
+			if tt.owner != "" {
+				cacher.values[key] = tt.owner
+			}
+			if tt.setup != nil {
+				tt.setup(cacher)
+			}
+
+			err := usecase.acquireSeatHold(context.Background(), key, "alice", time.Minute)
+			if err != tt.wantErr {
+				t.Fatalf("acquireSeatHold() error = %v, want %v", err, tt.wantErr)
+			}
+
+			wantOwner := "alice"
+			if tt.wantErr != nil {
+				wantOwner = tt.owner
+			}
+			if owner := cacher.values[key]; owner != wantOwner {
+				t.Errorf("hold owner = %q, want %q", owner, wantOwner)
+			}
+		})
+	}
+}
+
+func TestHoldSeat(t *testing.T) {
+	tests := []struct {
+		name     string
+		seat     string
+		holderID string
+		occupied []string
+		wantErr  error
+	}{
+		{name: "free seat", seat: "1a", holderID: "alice"},
+		{name: "no holder", seat: "1A", holderID: " ", wantErr: model.ErrInvalidHolder},
+		{name: "unknown seat", seat: "9A", holderID: "alice", wantErr: model.ErrSeatNotFound},
+		{name: "blocked seat", seat: "3B", holderID: "alice", wantErr: model.ErrSeatUnavailable},
+		{name: "sold seat", seat: "1A", holderID: "alice", occupied: []string{"1A"}, wantErr: model.ErrSeatTaken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase, repo, _ := newSeatHoldUsecase()
+			repo.occupied["GA402"] = tt.occupied
+
+			hold, err := usecase.HoldSeat("GA402", tt.seat, tt.holderID)
+			if err != tt.wantErr {
+				t.Fatalf("HoldSeat() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (hold.SeatNumber != "1A" || hold.HolderID != tt.holderID) {
+				t.Errorf("HoldSeat() = %+v", hold)
+			}
+		})
+	}
+}
+
+func TestReleaseSeatHold(t *testing.T) {
+	const key = "seat-hold:GA402:1A"
+
+	tests := []struct {
+		name      string
+		owner     string
+		wantErr   error
+		wantOwner string
+	}{
+		{name: "released by the owner", owner: "alice"},
+		{name: "held by another holder", owner: "bob", wantErr: model.ErrSeatHeld, wantOwner: "bob"},
+		{name: "no hold", wantErr: model.ErrSeatHoldNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase, _, cacher := newSeatHoldUsecase()
+			if tt.owner != "" {
+				cacher.values[key] = tt.owner
+			}
+
+			err := usecase.ReleaseSeatHold("1", "1a", "alice")
+			if err != tt.wantErr {
+				t.Fatalf("ReleaseSeatHold() error = %v, want %v", err, tt.wantErr)
+			}
+			if owner := cacher.values[key]; owner != tt.wantOwner {
+				t.Errorf("hold owner = %q, want %q", owner, tt.wantOwner)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"strconv"
+	"strings"
+)
+
+// GetSeatMap returns every seat of a flight's aircraft together with its occupancy
+func (s *FlightUsecase) GetSeatMap(id string) (model.SeatMap, error) {
+	flight, err := s.GetFlightByID(id)
+	if err != nil {
+		return model.SeatMap{}, err
+	}
+
+	layout, err := s.FlightRepo.GetSeatLayout(flight.AircraftType)
+	if err != nil {
+		return model.SeatMap{}, err
+	}
+
+	occupied, err := s.FlightRepo.GetOccupiedSeats(flight.FlightNumber)
+	if err != nil {
+		return model.SeatMap{}, err
+	}
+	taken := make(map[string]bool, len(occupied))
+	for _, seat := range occupied {
+		taken[seat] = true
+	}
+
+	seatMap := model.SeatMap{
+		FlightNumber: flight.FlightNumber,
+		AircraftType: layout.AircraftType,
+		Seats:        expandSeatLayout(layout),
+	}
+	for i := range seatMap.Seats {
+		if taken[seatMap.Seats[i].SeatNumber] {
+			seatMap.Seats[i].Occupied = true
+			seatMap.OccupiedSeats++
+		}
+	}
+	seatMap.TotalSeats = len(seatMap.Seats)
+
+	return seatMap, nil
+}
+
+// UpsertSeatLayout validates and stores the seat layout of an aircraft type
+func (s *FlightUsecase) UpsertSeatLayout(layout model.SeatLayout) (model.SeatLayout, error) {
+	layout.AircraftType = strings.ToUpper(strings.TrimSpace(layout.AircraftType))
+	layout.Columns = strings.ToUpper(strings.TrimSpace(layout.Columns))
+	for i, seat := range layout.BlockedSeats {
+		layout.BlockedSeats[i] = normalizeSeatNumber(seat)
+	}
+
+	if err := validateSeatLayout(layout); err != nil {
+		return model.SeatLayout{}, err
+	}
+	if err := s.FlightRepo.UpsertSeatLayout(layout); err != nil {
+		return model.SeatLayout{}, err
+	}
+
+	return layout, nil
+}
+
+// findSeat looks up a seat of the flight's layout, rejecting seats that do not exist or are blocked
+func (s *FlightUsecase) findSeat(flight model.Flight, seatNumber string) (model.Seat, error) {
+	layout, err := s.FlightRepo.GetSeatLayout(flight.AircraftType)
+	if err != nil {
+		return model.Seat{}, err
+	}
+
+	for _, seat := range expandSeatLayout(layout) {
+		if seat.SeatNumber != seatNumber {
+			continue
+		}
+		if seat.Blocked {
+			return model.Seat{}, model.ErrSeatUnavailable
+		}
+		return seat, nil
+	}
+
+	return model.Seat{}, model.ErrSeatNotFound
+}
+
+func validateSeatLayout(layout model.SeatLayout) error {
+	if layout.AircraftType == "" || layout.Rows <= 0 || layout.Columns == "" {
+		return model.ErrInvalidSeatLayout
+	}
+
+	columns := map[rune]bool{}
+	for _, column := range layout.Columns {
+		if column < 'A' || column > 'Z' || columns[column] {
+			return model.ErrInvalidSeatLayout
+		}
+		columns[column] = true
+	}
+
+	for _, cabin := range layout.Cabins {
+		if cabin.Class != model.CabinEconomy && cabin.Class != model.CabinBusiness {
+			return model.ErrInvalidSeatLayout
+		}
+		if cabin.FirstRow < 1 || cabin.LastRow > layout.Rows || cabin.FirstRow > cabin.LastRow {
+			return model.ErrInvalidSeatLayout
+		}
+	}
+
+	for _, row := range layout.ExitRows {
+		if row < 1 || row > layout.Rows {
+			return model.ErrInvalidSeatLayout
+		}
+	}
+
+	for _, seat := range layout.BlockedSeats {
+		row, column, ok := splitSeatNumber(seat)
+		if !ok || row > layout.Rows || !columns[column] {
+			return model.ErrInvalidSeatLayout
+		}
+	}
+
+	return nil
+}
+
+// expandSeatLayout lists every seat of a layout in row then column order
+func expandSeatLayout(layout model.SeatLayout) []model.Seat {
+	exitRows := map[int]bool{}
+	for _, row := range layout.ExitRows {
+		exitRows[row] = true
+	}
+	blocked := map[string]bool{}
+	for _, seat := range layout.BlockedSeats {
+		blocked[seat] = true
+	}
+
+	seats := make([]model.Seat, 0, layout.Rows*len(layout.Columns))
+	for row := 1; row <= layout.Rows; row++ {
+		cabin := cabinOfRow(layout, row)
+		for _, column := range layout.Columns {
+			number := strconv.Itoa(row) + string(column)
+			seats = append(seats, model.Seat{
+				SeatNumber: number,
+				Row:        row,
+				Column:     string(column),
+				Cabin:      cabin,
+				ExitRow:    exitRows[row],
+				Blocked:    blocked[number],
+			})
+		}
+	}
+
+	return seats
+}
+
+// cabinOfRow returns the cabin class of a row, rows outside any cabin block are economy
+func cabinOfRow(layout model.SeatLayout, row int) string {
+	for _, cabin := range layout.Cabins {
+		if row >= cabin.FirstRow && row <= cabin.LastRow {
+			return cabin.Class
+		}
+	}
+	return model.CabinEconomy
+}
+
+func normalizeSeatNumber(seatNumber string) string {
+	return strings.ToUpper(strings.TrimSpace(seatNumber))
+}
+
+// splitSeatNumber splits a seat number such as "12A" into its row and column
+func splitSeatNumber(seatNumber string) (row int, column rune, ok bool) {
+	if len(seatNumber) < 2 {
+		return 0, 0, false
+	}
+	row, err := strconv.Atoi(seatNumber[:len(seatNumber)-1])
+	if err != nil || row < 1 {
+		return 0, 0, false
+	}
+	return row, rune(seatNumber[len(seatNumber)-1]), true
+}
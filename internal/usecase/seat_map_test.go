@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"testing"
+)
+
+func TestValidateSeatLayout(t *testing.T) {
+	valid := model.SeatLayout{
+		AircraftType: "A320",
+		Rows:         30,
+		Columns:      "ABCDEF",
+		Cabins:       []model.Cabin{{Class: model.CabinBusiness, FirstRow: 1, LastRow: 3}},
+		ExitRows:     []int{12, 13},
+		BlockedSeats: []string{"30F"},
+	}
+
+	tests := []struct {
+		name   string
+		modify func(layout *model.SeatLayout)
+		valid  bool
+	}{
+		{name: "valid", modify: func(layout *model.SeatLayout) {}, valid: true},
+		{name: "no aircraft type", modify: func(layout *model.SeatLayout) { layout.AircraftType = "" }},
+		{name: "no rows", modify: func(layout *model.SeatLayout) { layout.Rows = 0 }},
+		{name: "no columns", modify: func(layout *model.SeatLayout) { layout.Columns = "" }},
+		{name: "repeated column", modify: func(layout *model.SeatLayout) { layout.Columns = "ABCA" }},
+		{name: "lower case column", modify: func(layout *model.SeatLayout) { layout.Columns = "abc" }},
+		{name: "unknown cabin", modify: func(layout *model.SeatLayout) { layout.Cabins[0].Class = "first" }},
+		{name: "cabin past last row", modify: func(layout *model.SeatLayout) { layout.Cabins[0].LastRow = 31 }},
+		{name: "cabin rows reversed", modify: func(layout *model.SeatLayout) { layout.Cabins[0].FirstRow = 4 }},
+		{name: "exit row out of range", modify: func(layout *model.SeatLayout) { layout.ExitRows = []int{0} }},
+		{name: "blocked seat past last row", modify: func(layout *model.SeatLayout) { layout.BlockedSeats = []string{"31A"} }},
+		{name: "blocked seat in unknown column", modify: func(layout *model.SeatLayout) { layout.BlockedSeats = []string{"10G"} }},
+		{name: "blocked seat malformed", modify: func(layout *model.SeatLayout) { layout.BlockedSeats = []string{"A"} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout := valid
+			layout.Cabins = append([]model.Cabin(nil), valid.Cabins...)
+			tt.modify(&layout)
+
+			err := validateSeatLayout(layout)
+			if tt.valid && err != nil {
+				t.Errorf("validateSeatLayout() error = %v", err)
+			}
+			if !tt.valid && err != model.ErrInvalidSeatLayout {
+				t.Errorf("validateSeatLayout() error = %v, want %v", err, model.ErrInvalidSeatLayout)
+			}
+		})
+	}
+}
+
+func TestExpandSeatLayout(t *testing.T) {
+	layout := model.SeatLayout{
+		AircraftType: "ATR72",
+		Rows:         3,
+		Columns:      "AB",
+		Cabins:       []model.Cabin{{Class: model.CabinBusiness, FirstRow: 1, LastRow: 1}},
+		ExitRows:     []int{2},
+		BlockedSeats: []string{"3B"},
+	}
+
+	want := []model.Seat{
+		{SeatNumber: "1A", Row: 1, Column: "A", Cabin: model.CabinBusiness},
+		{SeatNumber: "1B", Row: 1, Column: "B", Cabin: model.CabinBusiness},
+		{SeatNumber: "2A", Row: 2, Column: "A", Cabin: model.CabinEconomy, ExitRow: true},
+		{SeatNumber: "2B", Row: 2, Column: "B", Cabin: model.CabinEconomy, ExitRow: true},
+		{SeatNumber: "3A", Row: 3, Column: "A", Cabin: model.CabinEconomy},
+		{SeatNumber: "3B", Row: 3, Column: "B", Cabin: model.CabinEconomy, Blocked: true},
+	}
+
+	got := expandSeatLayout(layout)
+	if len(got) != len(want) {
+		t.Fatalf("expandSeatLayout() returned %d seats, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("seat %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
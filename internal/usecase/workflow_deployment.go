@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"booking-engine/config"
+	"booking-engine/internal/model"
+	"booking-engine/internal/repository"
+	"booking-engine/internal/workflow"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const deployDeadline = 30 * time.Second
+
+// WorkflowUsecase deploys the BPMN resources the booking processes run on
+type WorkflowUsecase struct {
+	FlightRepo repository.FlightPersister
+	Workflow   workflow.WorkflowEngine
+	Logger     config.Logger
+}
+
+type WorkflowExecutor interface {
+	DeployWorkflows(resources fs.FS) ([]model.WorkflowDeployment, error)
+}
+
+// NewWorkflowUsecaseService creates a new instance of the workflow service
+func NewWorkflowUsecaseService(workflowUsecase *WorkflowUsecase) WorkflowExecutor {
+	return workflowUsecase
+}
+
+// DeployWorkflows deploys every BPMN resource and returns the processes now deployed from the resources
+func (s *WorkflowUsecase) DeployWorkflows(resources fs.FS) ([]model.WorkflowDeployment, error) {
+	names, err := fs.Glob(resources, "*.bpmn")
+	if err != nil {
+		return nil, err
+	}
+
+	deployments := []model.WorkflowDeployment{}
+	for _, name := range names {
+		deployed, err := s.deployWorkflow(resources, name)
+		if err != nil {
+			return nil, fmt.Errorf("deploy %s: %w", name, err)
+		}
+		deployments = append(deployments, deployed...)
+	}
+
+	return deployments, nil
+}
+
+// PinDeployedVersion pins a process definition to the version of the process among the deployments,
+// so new instances are started from the version deployed on startup
+func PinDeployedVersion(process model.ProcessDefinition, deployments []model.WorkflowDeployment) (model.ProcessDefinition, error) {
+	process = defaultProcess(process)
+	for _, deployment := range deployments {
+		if deployment.ProcessID == process.ProcessID {
+			return model.ProcessDefinition{ProcessID: process.ProcessID, Version: deployment.Version}, nil
+		}
+	}
+	return model.ProcessDefinition{}, fmt.Errorf("process %s is not in the workflow resources", process.ProcessID)
+}
+
+// deployWorkflow always deploys the resource, the engine may have lost it even though it is recorded here, and
+// Zeebe does not create a new version of an unchanged process anyway. Only new versions are added to the history.
+func (s *WorkflowUsecase) deployWorkflow(resources fs.FS, name string) ([]model.WorkflowDeployment, error) {
+	content, err := fs.ReadFile(resources, name)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(content)
+	checksum := hex.EncodeToString(sum[:])
+
+	ctx, cancel := context.WithTimeout(context.Background(), deployDeadline)
+	defer cancel()
+	processes, err := s.Workflow.Deploy(ctx, name, content)
+	if err != nil {
+		return nil, workflowUnavailable("deploy workflow", err)
+	}
+
+	previous, err := s.FlightRepo.GetWorkflowDeployments(name)
+	if err != nil {
+		return nil, persistenceFailed("read workflow deployments", err)
+	}
+	recorded := map[string]model.WorkflowDeployment{}
+	for _, deployment := range previous {
+		recorded[deployment.ProcessID] = deployment
+	}
+
+	now := time.Now()
+	deployments := make([]model.WorkflowDeployment, 0, len(processes))
+	changed := []model.WorkflowDeployment{}
+	for _, process := range processes {
+		deployment := model.WorkflowDeployment{
+			ResourceName: name,
+			ProcessID:    process.ProcessID,
+			Checksum:     checksum,
+			Version:      process.Version,
+			ProcessKey:   process.ProcessKey,
+			DeployedAt:   now,
+		}
+		last, ok := recorded[process.ProcessID]
+		if ok && last.ProcessKey == deployment.ProcessKey && last.Checksum == checksum {
+			deployments = append(deployments, last)
+			continue
+		}
+
+		s.Logger.Info("workflow deployed", zap.String("resource", name), zap.String("process_id", process.ProcessID),
+			zap.Int32("version", process.Version))
+		deployments = append(deployments, deployment)
+		changed = append(changed, deployment)
+	}
+	if err := s.FlightRepo.RecordWorkflowDeployments(changed); err != nil {
+		return nil, persistenceFailed("record workflow deployment", err)
+	}
+
+	return deployments, nil
+}
+
+func (s *FlightUsecase) processDefinition() model.ProcessDefinition {
+	return defaultProcess(s.Process)
+}
+
+func defaultProcess(process model.ProcessDefinition) model.ProcessDefinition {
+	if process.ProcessID == "" {
+		process.ProcessID = DefaultProcessID
+	}
+	return process
+}
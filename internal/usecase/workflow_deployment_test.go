@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"booking-engine/internal/workflow"
+	"testing"
+	"testing/fstest"
+
+	"go.uber.org/zap"
+)
+
+func bpmnResource(processIDs ...string) *fstest.MapFile {
+	content := `<definitions xmlns="http://www.omg.org/spec/BPMN/20100524/MODEL">`
+	for _, processID := range processIDs {
+		content += `<process id="` + processID + `"/>`
+	}
+	return &fstest.MapFile{Data: []byte(content + `</definitions>`)}
+}
+
+func TestDeployWorkflows(t *testing.T) {
+	repo := newMemoryFlightRepository(model.Flight{}, model.SeatLayout{})
+	s := &WorkflowUsecase{FlightRepo: repo, Workflow: workflow.NewMemoryEngine(), Logger: zap.NewNop()}
+
+	steps := []struct {
+		name        string
+		resources   fstest.MapFS
+		wantVersion int32
+		wantHistory int
+	}{
+		{name: "first deployment", resources: fstest.MapFS{"booking.bpmn": bpmnResource(DefaultProcessID)}, wantVersion: 1, wantHistory: 1},
+		{name: "unchanged resource", resources: fstest.MapFS{"booking.bpmn": bpmnResource(DefaultProcessID)}, wantVersion: 1, wantHistory: 1},
+		{name: "changed resource", resources: fstest.MapFS{"booking.bpmn": bpmnResource(DefaultProcessID, "fww-refund")}, wantVersion: 2, wantHistory: 3},
+	}
+	for _, step := range steps {
+		deployments, err := s.DeployWorkflows(step.resources)
+		if err != nil {
+			t.Fatalf("%s: DeployWorkflows() error = %v", step.name, err)
+		}
+		process, err := PinDeployedVersion(model.ProcessDefinition{}, deployments)
+		if err != nil {
+			t.Fatalf("%s: PinDeployedVersion() error = %v", step.name, err)
+		}
+		want := model.ProcessDefinition{ProcessID: DefaultProcessID, Version: step.wantVersion}
+		if process != want {
+			t.Errorf("%s: pinned process = %+v, want %+v", step.name, process, want)
+		}
+		if len(repo.deployments) != step.wantHistory {
+			t.Errorf("%s: %d deployments recorded, want %d", step.name, len(repo.deployments), step.wantHistory)
+		}
+	}
+}
+
+func TestPinDeployedVersion(t *testing.T) {
+	deployments := []model.WorkflowDeployment{
+		{ProcessID: DefaultProcessID, Version: 3},
+		{ProcessID: "fww-refund", Version: 1},
+	}
+
+	tests := []struct {
+		name    string
+		process model.ProcessDefinition
+		want    model.ProcessDefinition
+		wantErr bool
+	}{
+		{name: "default process", want: model.ProcessDefinition{ProcessID: DefaultProcessID, Version: 3}},
+		{name: "configured process", process: model.ProcessDefinition{ProcessID: "fww-refund"},
+			want: model.ProcessDefinition{ProcessID: "fww-refund", Version: 1}},
+		{name: "deployed version wins over a configured one", process: model.ProcessDefinition{ProcessID: DefaultProcessID, Version: 1},
+			want: model.ProcessDefinition{ProcessID: DefaultProcessID, Version: 3}},
+		{name: "process not deployed", process: model.ProcessDefinition{ProcessID: "fww-unknown"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PinDeployedVersion(tt.process, deployments)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PinDeployedVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("PinDeployedVersion() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"booking-engine/internal/model"
+	"booking-engine/internal/workflow"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const workflowStatusDeadline = 15 * time.Second
+
+// GetReservationWorkflow returns where a reservation stands in its process. A reservation whose instance has not
+// been started yet reports how starting it from the outbox has gone so far instead.
+func (s *FlightUsecase) GetReservationWorkflow(id int) (model.WorkflowStatus, error) {
+	reservation, err := s.FlightRepo.GetBookingByID(id)
+	if err != nil {
+		return model.WorkflowStatus{}, err
+	}
+
+	if reservation.InstanceKey == 0 {
+		entry, err := s.FlightRepo.GetOutboxEntry(id)
+		if err != nil {
+			return model.WorkflowStatus{}, err
+		}
+
+		variables := map[string]interface{}{}
+		if err := json.Unmarshal(entry.Variables, &variables); err != nil {
+			return model.WorkflowStatus{}, err
+		}
+		return model.WorkflowStatus{
+			ReservationID:  id,
+			ProcessID:      entry.ProcessID,
+			Version:        entry.ProcessVersion,
+			State:          model.WorkflowNotStarted,
+			ActiveElements: []string{},
+			Incidents:      []string{},
+			Variables:      variables,
+			Outbox: &model.WorkflowOutboxStatus{
+				Attempts:      entry.Attempts,
+				NextAttemptAt: entry.NextAttemptAt,
+				LastError:     entry.LastError,
+			},
+		}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), workflowStatusDeadline)
+	defer cancel()
+	instance, err := s.Workflow.GetInstance(ctx, reservation.InstanceKey)
+	if err != nil {
+		switch {
+		case errors.Is(err, workflow.ErrInstanceNotFound):
+			return model.WorkflowStatus{}, model.ErrWorkflowInstanceNotFound
+		case errors.Is(err, workflow.ErrNoInspection):
+			return model.WorkflowStatus{}, model.ErrWorkflowNoInspection
+		}
+		return model.WorkflowStatus{}, workflowUnavailable("get process instance", err)
+	}
+
+	status := model.WorkflowStatus{
+		ReservationID:  id,
+		InstanceKey:    instance.InstanceKey,
+		ProcessID:      instance.ProcessID,
+		Version:        instance.Version,
+		State:          instance.State,
+		ActiveElements: instance.ActiveElements,
+		Incidents:      instance.Incidents,
+		Variables:      instance.Variables,
+	}
+	if status.ActiveElements == nil {
+		status.ActiveElements = []string{}
+	}
+	if status.Incidents == nil {
+		status.Incidents = []string{}
+	}
+	return status, nil
+}
@@ -0,0 +1,70 @@
+package worker
+
+import (
+	"booking-engine/config"
+	"booking-engine/internal/usecase"
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const DefaultExpiryInterval = time.Minute
+
+// ExpiryWorker periodically expires reservations that were not paid in time
+type ExpiryWorker struct {
+	Usecase  usecase.FlightExecutor
+	Interval time.Duration
+	Logger   config.Logger
+
+	expired  prometheus.Counter
+	failures prometheus.Counter
+}
+
+// NewExpiryWorker creates a new instance of the expiry worker and registers its metrics
+func NewExpiryWorker(worker ExpiryWorker, registerer prometheus.Registerer) *ExpiryWorker {
+	if worker.Interval <= 0 {
+		worker.Interval = DefaultExpiryInterval
+	}
+
+	worker.expired = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "fww_booking_reservations_expired_total",
+		Help: "Number of unpaid reservations expired by the expiry worker.",
+	})
+	worker.failures = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "fww_booking_expiry_failures_total",
+		Help: "Number of expiry runs that failed.",
+	})
+	registerer.MustRegister(worker.expired, worker.failures)
+
+	return &worker
+}
+
+// Run expires unpaid reservations every interval until the context is cancelled
+func (w *ExpiryWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.runOnce()
+		}
+	}
+}
+
+func (w *ExpiryWorker) runOnce() {
+	expired, err := w.Usecase.ExpireUnpaidReservations()
+	w.expired.Add(float64(expired))
+	if err != nil {
+		w.failures.Inc()
+		w.Logger.Error("failed to expire reservations", zap.Error(err))
+		return
+	}
+	if expired > 0 {
+		w.Logger.Info("expired unpaid reservations", zap.Int("count", expired))
+	}
+}
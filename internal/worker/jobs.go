@@ -0,0 +1,139 @@
+package worker
+
+import (
+	"booking-engine/config"
+	"booking-engine/internal/model"
+	"booking-engine/internal/usecase"
+	"booking-engine/internal/workflow"
+	"context"
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// Job types of the service tasks in the fww-bpm process
+const (
+	JobReserveSeat      = "reserve-seat"
+	JobConfirmPayment   = "confirm-payment"
+	JobIssueTicket      = "issue-ticket"
+	JobSendNotification = "send-notification"
+)
+
+var errNoReservation = errors.New("job variables have no reservation_id")
+
+// JobWorkers works on the service tasks of the booking process
+type JobWorkers struct {
+	Usecase usecase.FlightExecutor
+	Engine  workflow.WorkflowEngine
+	Logger  config.Logger
+
+	jobs *prometheus.CounterVec
+}
+
+// NewJobWorkers creates a new instance of the job workers and registers their metrics
+func NewJobWorkers(workers JobWorkers, registerer prometheus.Registerer) *JobWorkers {
+	workers.jobs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "fww_booking_jobs_total",
+		Help: "Number of workflow jobs handled, by job type and result.",
+	}, []string{"job_type", "result"})
+	registerer.MustRegister(workers.jobs)
+
+	return &workers
+}
+
+// Register starts handling every service task type of the booking process
+func (w *JobWorkers) Register() error {
+	handlers := map[string]workflow.JobHandler{
+		JobReserveSeat:      w.reserveSeat,
+		JobConfirmPayment:   w.confirmPayment,
+		JobIssueTicket:      w.issueTicket,
+		JobSendNotification: w.sendNotification,
+	}
+
+	for jobType, handler := range handlers {
+		if err := w.Engine.HandleJobs(jobType, w.counted(jobType, handler)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reserveSeat confirms the seats of the reservation are still held by it. They are claimed when the booking is
+// saved, so only a reservation closed since then has lost them.
+func (w *JobWorkers) reserveSeat(ctx context.Context, job workflow.Job) (map[string]interface{}, error) {
+	reservation, err := w.reservation(job)
+	if err != nil {
+		return nil, err
+	}
+
+	reserved := reservation.Status != model.ReservationCancelled && reservation.Status != model.ReservationExpired
+	return map[string]interface{}{"seats_reserved": reserved}, nil
+}
+
+// confirmPayment reports whether the reservation has been paid
+func (w *JobWorkers) confirmPayment(ctx context.Context, job workflow.Job) (map[string]interface{}, error) {
+	reservation, err := w.reservation(job)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{"status_payment": reservation.PaymentStatus == model.PaymentPaid}, nil
+}
+
+// issueTicket moves a paid reservation to ticketed. A job retried after the ticket was issued succeeds again.
+func (w *JobWorkers) issueTicket(ctx context.Context, job workflow.Job) (map[string]interface{}, error) {
+	reservation, err := w.reservation(job)
+	if err != nil {
+		return nil, err
+	}
+
+	if reservation.Status != model.ReservationTicketed {
+		if _, err := w.Usecase.TransitionReservation(reservation.ReservationID, model.ReservationTicketed); err != nil {
+			return nil, err
+		}
+	}
+	return map[string]interface{}{"ticketed": true}, nil
+}
+
+// sendNotification tells the customer where their booking stands. There is no notification channel yet,
+// so the notification is only logged.
+func (w *JobWorkers) sendNotification(ctx context.Context, job workflow.Job) (map[string]interface{}, error) {
+	reservation, err := w.reservation(job)
+	if err != nil {
+		return nil, err
+	}
+
+	w.Logger.Info("booking notification", zap.Int("reservation_id", reservation.ReservationID),
+		zap.String("locator", reservation.Locator), zap.String("status", reservation.Status),
+		zap.Int("passengers", len(reservation.Passengers)))
+	return map[string]interface{}{"notified": true}, nil
+}
+
+func (w *JobWorkers) reservation(job workflow.Job) (model.Reservation, error) {
+	var variables model.BookingVariables
+	if err := job.VariablesAs(&variables); err != nil {
+		return model.Reservation{}, err
+	}
+	if variables.ReservationID <= 0 {
+		return model.Reservation{}, errNoReservation
+	}
+
+	booking, err := w.Usecase.GetBookingByID(variables.ReservationID)
+	if err != nil {
+		return model.Reservation{}, err
+	}
+	return booking.Reservation, nil
+}
+
+func (w *JobWorkers) counted(jobType string, handler workflow.JobHandler) workflow.JobHandler {
+	return func(ctx context.Context, job workflow.Job) (map[string]interface{}, error) {
+		variables, err := handler(ctx, job)
+		if err != nil {
+			w.jobs.WithLabelValues(jobType, "failed").Inc()
+			return nil, err
+		}
+		w.jobs.WithLabelValues(jobType, "completed").Inc()
+		return variables, nil
+	}
+}
@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"booking-engine/config"
+	"booking-engine/internal/usecase"
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const DefaultOutboxInterval = 2 * time.Second
+
+// OutboxDispatcher periodically starts the process instances queued in the workflow outbox
+type OutboxDispatcher struct {
+	Usecase  usecase.FlightExecutor
+	Interval time.Duration
+	Logger   config.Logger
+
+	dispatched prometheus.Counter
+	failures   prometheus.Counter
+}
+
+// NewOutboxDispatcher creates a new instance of the outbox dispatcher and registers its metrics
+func NewOutboxDispatcher(dispatcher OutboxDispatcher, registerer prometheus.Registerer) *OutboxDispatcher {
+	if dispatcher.Interval <= 0 {
+		dispatcher.Interval = DefaultOutboxInterval
+	}
+
+	dispatcher.dispatched = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "fww_booking_outbox_dispatched_total",
+		Help: "Number of process instances started from the workflow outbox.",
+	})
+	dispatcher.failures = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "fww_booking_outbox_failures_total",
+		Help: "Number of outbox dispatch runs that failed.",
+	})
+	registerer.MustRegister(dispatcher.dispatched, dispatcher.failures)
+
+	return &dispatcher
+}
+
+// Run dispatches the outbox every interval until the context is cancelled
+func (d *OutboxDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.runOnce()
+		}
+	}
+}
+
+func (d *OutboxDispatcher) runOnce() {
+	dispatched, err := d.Usecase.DispatchWorkflowOutbox()
+	d.dispatched.Add(float64(dispatched))
+	if err != nil {
+		d.failures.Inc()
+		d.Logger.Error("failed to dispatch workflow outbox", zap.Error(err))
+	}
+}
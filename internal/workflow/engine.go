@@ -0,0 +1,73 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var (
+	ErrInstanceNotFound = errors.New("process instance not found")
+	ErrNoJobHandler     = errors.New("no handler registered for the job type")
+	ErrNoInspection     = errors.New("process instances cannot be inspected without operate")
+)
+
+// Message is a message published to the process instances waiting on its name and correlation key
+type Message struct {
+	Name           string
+	CorrelationKey string
+	// MessageID makes publishing idempotent, a message with the same ID is only delivered once within its TTL
+	MessageID string
+	TTL       time.Duration
+	Variables interface{}
+}
+
+// Job is a service task of a process instance waiting to be worked on
+type Job struct {
+	Key         int64
+	Type        string
+	InstanceKey int64
+	// Retries is what is left of the retries of the job, including the current attempt
+	Retries   int32
+	Variables json.RawMessage
+}
+
+// VariablesAs decodes the variables of the job's process instance into v
+func (j Job) VariablesAs(v interface{}) error {
+	return json.Unmarshal(j.Variables, v)
+}
+
+// JobHandler works on a job and returns the variables to complete it with. A returned error fails the job,
+// which the engine retries until the job runs out of retries.
+type JobHandler func(ctx context.Context, job Job) (map[string]interface{}, error)
+
+// DeployedProcess is a process definition created by a deployment
+type DeployedProcess struct {
+	ProcessID  string
+	Version    int32
+	ProcessKey int64
+}
+
+// InstanceStatus is where a process instance stands
+type InstanceStatus struct {
+	InstanceKey int64
+	ProcessID   string
+	Version     int32
+	State       string
+	// ActiveElements are the IDs of the BPMN elements the instance is waiting in
+	ActiveElements []string
+	Incidents      []string
+	Variables      map[string]interface{}
+}
+
+// WorkflowEngine is implemented by every BPMN engine the booking flow can run its processes on
+type WorkflowEngine interface {
+	Deploy(ctx context.Context, resourceName string, content []byte) ([]DeployedProcess, error)
+	StartProcess(ctx context.Context, processID string, version int32, variables interface{}) (instanceKey int64, err error)
+	PublishMessage(ctx context.Context, message Message) error
+	CancelInstance(ctx context.Context, instanceKey int64) error
+	GetInstance(ctx context.Context, instanceKey int64) (InstanceStatus, error)
+	HandleJobs(jobType string, handler JobHandler) error
+	Close() error
+}
@@ -0,0 +1,273 @@
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"sync"
+)
+
+const (
+	InstanceActive    = "ACTIVE"
+	InstanceCompleted = "COMPLETED"
+	InstanceCancelled = "CANCELED"
+)
+
+// Instance is a process instance tracked by the in-memory engine
+type Instance struct {
+	InstanceKey int64
+	ProcessID   string
+	Version     int32
+	State       string
+	Variables   map[string]interface{}
+	Messages    []Message
+}
+
+// MemoryEngine is a workflow engine that only records what it is asked to do, for tests and running without Zeebe.
+// Published messages are delivered to the active instances whose "reservation_id" variable matches the correlation key.
+type MemoryEngine struct {
+	mu        sync.Mutex
+	nextKey   int64
+	instances map[int64]*Instance
+	delivered map[string]bool
+	handlers  map[string]JobHandler
+	resources map[string][]byte
+	deployed  map[string][]DeployedProcess
+	versions  map[string]int32
+}
+
+// NewMemoryEngine creates a new instance of the in-memory workflow engine
+func NewMemoryEngine() *MemoryEngine {
+	return &MemoryEngine{
+		nextKey:   1,
+		instances: map[int64]*Instance{},
+		delivered: map[string]bool{},
+		handlers:  map[string]JobHandler{},
+		resources: map[string][]byte{},
+		deployed:  map[string][]DeployedProcess{},
+		versions:  map[string]int32{},
+	}
+}
+
+// Deploy records a new version of every process in a BPMN resource, unless the resource is unchanged
+func (e *MemoryEngine) Deploy(ctx context.Context, resourceName string, content []byte) ([]DeployedProcess, error) {
+	var definitions struct {
+		Processes []struct {
+			ID string `xml:"id,attr"`
+		} `xml:"process"`
+	}
+	if err := xml.Unmarshal(content, &definitions); err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if previous, ok := e.resources[resourceName]; ok && bytes.Equal(previous, content) {
+		return e.deployed[resourceName], nil
+	}
+
+	processes := make([]DeployedProcess, 0, len(definitions.Processes))
+	for _, process := range definitions.Processes {
+		e.versions[process.ID]++
+		processes = append(processes, DeployedProcess{
+			ProcessID:  process.ID,
+			Version:    e.versions[process.ID],
+			ProcessKey: e.newKey(),
+		})
+	}
+	e.resources[resourceName] = content
+	e.deployed[resourceName] = processes
+	return processes, nil
+}
+
+// StartProcess records a new active instance of the process
+func (e *MemoryEngine) StartProcess(ctx context.Context, processID string, version int32, variables interface{}) (int64, error) {
+	values, err := toVariables(variables)
+	if err != nil {
+		return 0, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if version == 0 {
+		version = e.versions[processID]
+	}
+	key := e.newKey()
+	e.instances[key] = &Instance{
+		InstanceKey: key,
+		ProcessID:   processID,
+		Version:     version,
+		State:       InstanceActive,
+		Variables:   values,
+	}
+	return key, nil
+}
+
+// PublishMessage delivers the message to the matching active instances, once per message ID
+func (e *MemoryEngine) PublishMessage(ctx context.Context, message Message) error {
+	values, err := toVariables(message.Variables)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if message.MessageID != "" {
+		id := message.Name + "/" + message.MessageID
+		if e.delivered[id] {
+			return nil
+		}
+		e.delivered[id] = true
+	}
+
+	for _, instance := range e.instances {
+		if instance.State != InstanceActive || correlationKey(instance) != message.CorrelationKey {
+			continue
+		}
+		instance.Messages = append(instance.Messages, message)
+		for name, value := range values {
+			instance.Variables[name] = value
+		}
+	}
+	return nil
+}
+
+// CancelInstance marks an active instance cancelled
+func (e *MemoryEngine) CancelInstance(ctx context.Context, instanceKey int64) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	instance, ok := e.instances[instanceKey]
+	if !ok || instance.State != InstanceActive {
+		return ErrInstanceNotFound
+	}
+	instance.State = InstanceCancelled
+	return nil
+}
+
+// GetInstance returns the state and variables of a tracked instance. The in-memory engine does not execute
+// the process, so it never reports active elements or incidents.
+func (e *MemoryEngine) GetInstance(ctx context.Context, instanceKey int64) (InstanceStatus, error) {
+	instance, err := e.Instance(instanceKey)
+	if err != nil {
+		return InstanceStatus{}, err
+	}
+
+	return InstanceStatus{
+		InstanceKey: instance.InstanceKey,
+		ProcessID:   instance.ProcessID,
+		Version:     instance.Version,
+		State:       instance.State,
+		Variables:   instance.Variables,
+	}, nil
+}
+
+// Instance returns a copy of a tracked instance
+func (e *MemoryEngine) Instance(instanceKey int64) (Instance, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	instance, ok := e.instances[instanceKey]
+	if !ok {
+		return Instance{}, ErrInstanceNotFound
+	}
+
+	copied := *instance
+	copied.Variables = make(map[string]interface{}, len(instance.Variables))
+	for name, value := range instance.Variables {
+		copied.Variables[name] = value
+	}
+	copied.Messages = append([]Message(nil), instance.Messages...)
+	return copied, nil
+}
+
+// HandleJobs registers the handler RunJob calls for jobs of the type
+func (e *MemoryEngine) HandleJobs(jobType string, handler JobHandler) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.handlers[jobType] = handler
+	return nil
+}
+
+// RunJob works on a job of the type for an active instance the way a job worker would, merging the variables
+// the handler completes it with into the instance
+func (e *MemoryEngine) RunJob(ctx context.Context, instanceKey int64, jobType string) (map[string]interface{}, error) {
+	e.mu.Lock()
+	handler, ok := e.handlers[jobType]
+	instance, found := e.instances[instanceKey]
+	if !ok || !found || instance.State != InstanceActive {
+		e.mu.Unlock()
+		if !ok {
+			return nil, ErrNoJobHandler
+		}
+		return nil, ErrInstanceNotFound
+	}
+	raw, err := json.Marshal(instance.Variables)
+	job := Job{
+		Key:         e.newKey(),
+		Type:        jobType,
+		InstanceKey: instanceKey,
+		Retries:     1,
+		Variables:   raw,
+	}
+	e.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	// The handler runs unlocked, it may well call back into the engine
+	variables, err := handler(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for name, value := range variables {
+		instance.Variables[name] = value
+	}
+	return variables, nil
+}
+
+// Close does nothing, the in-memory engine holds no connections
+func (e *MemoryEngine) Close() error {
+	return nil
+}
+
+// newKey hands out the keys of instances, jobs and deployed processes, the caller holds the lock
+func (e *MemoryEngine) newKey() int64 {
+	key := e.nextKey
+	e.nextKey++
+	return key
+}
+
+// toVariables turns a variables object into the JSON document an engine would store
+func toVariables(variables interface{}) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	if variables == nil {
+		return values, nil
+	}
+
+	raw, err := json.Marshal(variables)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func correlationKey(instance *Instance) string {
+	value, ok := instance.Variables["reservation_id"]
+	if !ok {
+		return ""
+	}
+	raw, _ := json.Marshal(value)
+	return string(raw)
+}
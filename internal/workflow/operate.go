@@ -0,0 +1,152 @@
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	operateTimeout    = 10 * time.Second
+	operateSearchSize = 1000
+)
+
+// OperateClient reads process instances from the Operate REST API
+type OperateClient struct {
+	BaseURL string
+	Token   string
+	HTTP    *http.Client
+}
+
+// NewOperateClient creates a new instance of the Operate client, token may be empty for an unsecured Operate
+func NewOperateClient(baseURL string, token string) *OperateClient {
+	return &OperateClient{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		Token:   token,
+		HTTP:    &http.Client{Timeout: operateTimeout},
+	}
+}
+
+type operateInstance struct {
+	Key            int64  `json:"key"`
+	BpmnProcessID  string `json:"bpmnProcessId"`
+	ProcessVersion int32  `json:"processVersion"`
+	State          string `json:"state"`
+}
+
+type operateVariable struct {
+	ScopeKey int64  `json:"scopeKey"`
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+}
+
+type operateFlowNode struct {
+	FlowNodeID string `json:"flowNodeId"`
+}
+
+type operateIncident struct {
+	Message string `json:"message"`
+}
+
+// GetInstance returns the state of an instance together with its active elements, open incidents and variables
+func (c *OperateClient) GetInstance(ctx context.Context, instanceKey int64) (InstanceStatus, error) {
+	var instance operateInstance
+	if err := c.do(ctx, http.MethodGet, "/v1/process-instances/"+strconv.FormatInt(instanceKey, 10), nil, &instance); err != nil {
+		return InstanceStatus{}, err
+	}
+
+	status := InstanceStatus{
+		InstanceKey:    instance.Key,
+		ProcessID:      instance.BpmnProcessID,
+		Version:        instance.ProcessVersion,
+		State:          instance.State,
+		ActiveElements: []string{},
+		Incidents:      []string{},
+		Variables:      map[string]interface{}{},
+	}
+
+	var flowNodes []operateFlowNode
+	filter := map[string]interface{}{"processInstanceKey": instanceKey, "state": "ACTIVE"}
+	if err := c.search(ctx, "/v1/flownode-instances/search", filter, &flowNodes); err != nil {
+		return InstanceStatus{}, err
+	}
+	for _, flowNode := range flowNodes {
+		status.ActiveElements = append(status.ActiveElements, flowNode.FlowNodeID)
+	}
+
+	var incidents []operateIncident
+	if err := c.search(ctx, "/v1/incidents/search", filter, &incidents); err != nil {
+		return InstanceStatus{}, err
+	}
+	for _, incident := range incidents {
+		status.Incidents = append(status.Incidents, incident.Message)
+	}
+
+	var variables []operateVariable
+	filter = map[string]interface{}{"processInstanceKey": instanceKey}
+	if err := c.search(ctx, "/v1/variables/search", filter, &variables); err != nil {
+		return InstanceStatus{}, err
+	}
+	for _, variable := range variables {
+		// Variables local to a task are left out, only those of the process itself describe the booking
+		if variable.ScopeKey != instanceKey {
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(variable.Value), &value); err != nil {
+			// Operate truncates large values, which leaves them as the raw string
+			value = variable.Value
+		}
+		status.Variables[variable.Name] = value
+	}
+
+	return status, nil
+}
+
+func (c *OperateClient) search(ctx context.Context, path string, filter map[string]interface{}, items interface{}) error {
+	request := map[string]interface{}{"filter": filter, "size": operateSearchSize}
+	response := struct {
+		Items interface{} `json:"items"`
+	}{Items: items}
+
+	return c.do(ctx, http.MethodPost, path, request, &response)
+}
+
+func (c *OperateClient) do(ctx context.Context, method string, path string, body interface{}, out interface{}) error {
+	reader := bytes.NewReader(nil)
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(raw)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if c.Token != "" {
+		request.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	response, err := c.HTTP.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return ErrInstanceNotFound
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("operate %s %s returned %d", method, path, response.StatusCode)
+	}
+	return json.NewDecoder(response.Body).Decode(out)
+}
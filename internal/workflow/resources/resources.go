@@ -0,0 +1,9 @@
+package resources
+
+import "embed"
+
+// FS holds the BPMN processes of the booking engine, deployed to the workflow engine on startup.
+// DMN decisions can only be deployed from Zeebe 8 on, the client in use deploys BPMN only.
+//
+//go:embed *.bpmn
+var FS embed.FS
@@ -0,0 +1,201 @@
+package workflow
+
+import (
+	"booking-engine/config"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/camunda-cloud/zeebe/clients/go/pkg/commands"
+	"github.com/camunda-cloud/zeebe/clients/go/pkg/entities"
+	"github.com/camunda-cloud/zeebe/clients/go/pkg/worker"
+	"github.com/camunda-cloud/zeebe/clients/go/pkg/zbc"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	DefaultZeebeAddr = "0.0.0.0:26500"
+
+	jobWorkerName = "fww-booking"
+	jobTimeout    = time.Minute
+)
+
+// ZeebeEngine runs processes on a Zeebe cluster through one long-lived client
+type ZeebeEngine struct {
+	Client zbc.Client
+	Logger config.Logger
+	// Operate answers what the gateway cannot, where an instance stands. Without it instances cannot be inspected.
+	Operate *OperateClient
+
+	mu      sync.Mutex
+	workers []worker.JobWorker
+}
+
+// NewZeebeEngine connects to the Zeebe gateway at gatewayAddr, falling back to a local plaintext gateway
+func NewZeebeEngine(gatewayAddr string, logger config.Logger, operate *OperateClient) (WorkflowEngine, error) {
+	plainText := false
+	if gatewayAddr == "" {
+		gatewayAddr = DefaultZeebeAddr
+		plainText = true
+	}
+
+	client, err := zbc.NewClient(&zbc.ClientConfig{
+		GatewayAddress:         gatewayAddr,
+		UsePlaintextConnection: plainText,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ZeebeEngine{Client: client, Logger: logger, Operate: operate}, nil
+}
+
+// Deploy deploys a BPMN resource. Zeebe only creates a new version of a process whose definition changed.
+func (e *ZeebeEngine) Deploy(ctx context.Context, resourceName string, content []byte) ([]DeployedProcess, error) {
+	response, err := e.Client.NewDeployProcessCommand().AddResource(content, resourceName).Send(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	processes := make([]DeployedProcess, 0, len(response.GetProcesses()))
+	for _, process := range response.GetProcesses() {
+		processes = append(processes, DeployedProcess{
+			ProcessID:  process.GetBpmnProcessId(),
+			Version:    process.GetVersion(),
+			ProcessKey: process.GetProcessDefinitionKey(),
+		})
+	}
+	return processes, nil
+}
+
+// StartProcess creates an instance of the given version of the process, or of its latest version when version is zero
+func (e *ZeebeEngine) StartProcess(ctx context.Context, processID string, version int32, variables interface{}) (int64, error) {
+	step := e.Client.NewCreateInstanceCommand().BPMNProcessId(processID)
+	var versioned commands.CreateInstanceCommandStep3
+	if version > 0 {
+		versioned = step.Version(version)
+	} else {
+		versioned = step.LatestVersion()
+	}
+
+	command, err := versioned.VariablesFromObject(variables)
+	if err != nil {
+		return 0, err
+	}
+
+	response, err := command.Send(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return response.GetProcessInstanceKey(), nil
+}
+
+// PublishMessage publishes a message correlated by its correlation key
+func (e *ZeebeEngine) PublishMessage(ctx context.Context, message Message) error {
+	step := e.Client.NewPublishMessageCommand().
+		MessageName(message.Name).
+		CorrelationKey(message.CorrelationKey).
+		MessageId(message.MessageID).
+		TimeToLive(message.TTL)
+
+	if message.Variables != nil {
+		var err error
+		if step, err = step.VariablesFromObject(message.Variables); err != nil {
+			return err
+		}
+	}
+
+	_, err := step.Send(ctx)
+	return err
+}
+
+// CancelInstance cancels a running process instance
+func (e *ZeebeEngine) CancelInstance(ctx context.Context, instanceKey int64) error {
+	_, err := e.Client.NewCancelInstanceCommand().ProcessInstanceKey(instanceKey).Send(ctx)
+	if status.Code(err) == codes.NotFound {
+		return ErrInstanceNotFound
+	}
+	return err
+}
+
+// GetInstance looks the instance up in Operate
+func (e *ZeebeEngine) GetInstance(ctx context.Context, instanceKey int64) (InstanceStatus, error) {
+	if e.Operate == nil {
+		return InstanceStatus{}, ErrNoInspection
+	}
+	return e.Operate.GetInstance(ctx, instanceKey)
+}
+
+// HandleJobs opens a job worker that activates jobs of the type and works on them with the handler
+func (e *ZeebeEngine) HandleJobs(jobType string, handler JobHandler) error {
+	jobWorker := e.Client.NewJobWorker().
+		JobType(jobType).
+		Handler(e.jobHandler(handler)).
+		Name(jobWorkerName).
+		Timeout(jobTimeout).
+		Open()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.workers = append(e.workers, jobWorker)
+	return nil
+}
+
+// jobHandler completes a job with the variables its handler returns, or fails it with one retry less
+func (e *ZeebeEngine) jobHandler(handler JobHandler) worker.JobHandler {
+	return func(client worker.JobClient, activated entities.Job) {
+		ctx, cancel := context.WithTimeout(context.Background(), jobTimeout)
+		defer cancel()
+
+		job := Job{
+			Key:         activated.GetKey(),
+			Type:        activated.GetType(),
+			InstanceKey: activated.GetProcessInstanceKey(),
+			Retries:     activated.GetRetries(),
+			Variables:   json.RawMessage(activated.GetVariables()),
+		}
+		fields := []zap.Field{zap.String("job_type", job.Type), zap.Int64("job_key", job.Key),
+			zap.Int64("instance_key", job.InstanceKey)}
+
+		variables, err := handler(ctx, job)
+		if err == nil {
+			if err = completeJob(ctx, client, job.Key, variables); err == nil {
+				return
+			}
+		}
+
+		e.Logger.Error("job failed", append(fields, zap.Int32("retries", job.Retries-1), zap.Error(err))...)
+		_, err = client.NewFailJobCommand().JobKey(job.Key).Retries(job.Retries - 1).ErrorMessage(err.Error()).Send(ctx)
+		if err != nil {
+			e.Logger.Error("failed to fail job", append(fields, zap.Error(err))...)
+		}
+	}
+}
+
+func completeJob(ctx context.Context, client worker.JobClient, jobKey int64, variables map[string]interface{}) error {
+	if variables == nil {
+		variables = map[string]interface{}{}
+	}
+
+	command, err := client.NewCompleteJobCommand().JobKey(jobKey).VariablesFromMap(variables)
+	if err != nil {
+		return err
+	}
+	_, err = command.Send(ctx)
+	return err
+}
+
+// Close stops the job workers and closes the connection to the gateway
+func (e *ZeebeEngine) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, jobWorker := range e.workers {
+		jobWorker.Close()
+	}
+	e.workers = nil
+	return e.Client.Close()
+}
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS flights (
+    flight_id       INT AUTO_INCREMENT PRIMARY KEY,
+    flight_number   VARCHAR(16)    NOT NULL,
+    departure       VARCHAR(8)     NOT NULL,
+    destination     VARCHAR(8)     NOT NULL,
+    departure_time  DATETIME       NOT NULL,
+    price           DECIMAL(12, 2) NOT NULL,
+    available_seats INT            NOT NULL DEFAULT 0,
+    created_at      DATETIME       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at      DATETIME       NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_flights_flight_number (flight_number)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
@@ -0,0 +1 @@
+CREATE INDEX idx_flights_route_departure ON flights (departure, destination, departure_time);
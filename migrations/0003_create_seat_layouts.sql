@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS seat_layouts (
+    aircraft_type VARCHAR(32) PRIMARY KEY,
+    seat_rows     INT         NOT NULL,
+    seat_columns  VARCHAR(16) NOT NULL,
+    cabins        JSON        NOT NULL,
+    exit_rows     JSON        NOT NULL,
+    blocked_seats JSON        NOT NULL,
+    updated_at    DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
+
+ALTER TABLE flights ADD COLUMN aircraft_type VARCHAR(32) NOT NULL DEFAULT '' AFTER destination;
+
+CREATE TABLE IF NOT EXISTS flight_seats (
+    flight_number  VARCHAR(16) NOT NULL,
+    seat_number    VARCHAR(8)  NOT NULL,
+    reservation_id INT         NOT NULL,
+    created_at     DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (flight_number, seat_number),
+    KEY idx_flight_seats_reservation (reservation_id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS reservation_passengers (
+    reservation_passenger_id INT AUTO_INCREMENT PRIMARY KEY,
+    reservation_id           INT            NOT NULL,
+    passenger_id             INT            NOT NULL,
+    passenger_type           VARCHAR(16)    NOT NULL,
+    seat_number              VARCHAR(8)     NOT NULL DEFAULT '',
+    fare_class               VARCHAR(16)    NOT NULL,
+    price                    DECIMAL(12, 2) NOT NULL,
+    KEY idx_reservation_passengers_reservation (reservation_id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
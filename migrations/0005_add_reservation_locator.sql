@@ -0,0 +1,3 @@
+ALTER TABLE reservations
+    ADD COLUMN locator CHAR(6) NULL AFTER reservation_id,
+    ADD UNIQUE KEY uq_reservations_locator (locator);
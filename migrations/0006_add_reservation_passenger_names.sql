@@ -0,0 +1,3 @@
+ALTER TABLE reservation_passengers
+    ADD COLUMN first_name VARCHAR(64) NOT NULL DEFAULT '' AFTER passenger_id,
+    ADD COLUMN last_name  VARCHAR(64) NOT NULL DEFAULT '' AFTER first_name;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS passengers (
+    passenger_id    INT AUTO_INCREMENT PRIMARY KEY,
+    first_name      VARCHAR(64)  NOT NULL,
+    last_name       VARCHAR(64)  NOT NULL,
+    date_of_birth   DATE         NOT NULL,
+    gender          VARCHAR(8)   NOT NULL,
+    email           VARCHAR(255) NOT NULL DEFAULT '',
+    phone           VARCHAR(32)  NOT NULL DEFAULT '',
+    nationality     CHAR(2)      NOT NULL,
+    document_type   VARCHAR(16)  NOT NULL,
+    document_number VARCHAR(32)  NOT NULL,
+    created_at      DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at      DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    KEY idx_passengers_document (document_type, document_number)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
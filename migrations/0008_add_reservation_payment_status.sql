@@ -0,0 +1,2 @@
+ALTER TABLE reservations
+    ADD COLUMN payment_status VARCHAR(16) NOT NULL DEFAULT 'UNPAID' AFTER price;
@@ -0,0 +1,3 @@
+CREATE INDEX idx_reservations_flight_number ON reservations (flight_number);
+CREATE INDEX idx_reservations_created_at ON reservations (created_at);
+CREATE INDEX idx_reservation_passengers_passenger ON reservation_passengers (passenger_id);
@@ -0,0 +1,9 @@
+ALTER TABLE reservations
+    ADD COLUMN status       VARCHAR(16) NOT NULL DEFAULT 'PENDING' AFTER payment_status,
+    ADD COLUMN paid_at      DATETIME    NULL AFTER created_at,
+    ADD COLUMN ticketed_at  DATETIME    NULL AFTER paid_at,
+    ADD COLUMN cancelled_at DATETIME    NULL AFTER ticketed_at,
+    ADD COLUMN expired_at   DATETIME    NULL AFTER cancelled_at,
+    ADD KEY idx_reservations_status (status);
+
+UPDATE reservations SET status = 'PAID', paid_at = created_at WHERE payment_status = 'PAID';
@@ -0,0 +1,2 @@
+ALTER TABLE reservations
+    ADD COLUMN refund_amount DECIMAL(12, 2) NOT NULL DEFAULT 0 AFTER price;
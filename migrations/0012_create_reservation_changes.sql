@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS reservation_changes (
+    change_id         INT AUTO_INCREMENT PRIMARY KEY,
+    reservation_id    INT            NOT NULL,
+    old_flight_number VARCHAR(16)    NOT NULL,
+    new_flight_number VARCHAR(16)    NOT NULL,
+    passengers        JSON           NOT NULL,
+    fare_difference   DECIMAL(12, 2) NOT NULL,
+    change_fee        DECIMAL(12, 2) NOT NULL,
+    created_at        DATETIME       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_reservation_changes_reservation (reservation_id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
+
+ALTER TABLE reservation_passengers
+    ADD COLUMN fare_basis VARCHAR(16) NOT NULL DEFAULT '' AFTER fare_class;
+
+UPDATE reservation_passengers SET fare_basis = fare_class WHERE fare_basis = '';
@@ -0,0 +1 @@
+CREATE INDEX idx_reservations_expiry ON reservations (status, payment_status, created_at);
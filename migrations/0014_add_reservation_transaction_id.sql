@@ -0,0 +1,2 @@
+ALTER TABLE reservations
+    ADD COLUMN transaction_id VARCHAR(64) NOT NULL DEFAULT '' AFTER payment_status;
@@ -0,0 +1,6 @@
+ALTER TABLE reservations
+    ADD COLUMN payment_reference VARCHAR(64) NOT NULL DEFAULT '' AFTER transaction_id;
+
+ALTER TABLE reservation_changes
+    ADD COLUMN charge_id VARCHAR(64) NULL AFTER change_fee,
+    ADD UNIQUE KEY uq_reservation_changes_charge (charge_id);
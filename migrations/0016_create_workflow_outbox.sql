@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS workflow_outbox (
+    outbox_id       INT AUTO_INCREMENT PRIMARY KEY,
+    reservation_id  INT          NOT NULL,
+    process_id      VARCHAR(64)  NOT NULL,
+    variables       JSON         NOT NULL,
+    attempts        INT          NOT NULL DEFAULT 0,
+    next_attempt_at DATETIME     NOT NULL,
+    last_error      VARCHAR(512) NOT NULL DEFAULT '',
+    dispatched_at   DATETIME     NULL,
+    created_at      DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_workflow_outbox_reservation (reservation_id, process_id),
+    KEY idx_workflow_outbox_pending (dispatched_at, next_attempt_at)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
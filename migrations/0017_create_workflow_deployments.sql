@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS workflow_deployments (
+    deployment_id INT AUTO_INCREMENT PRIMARY KEY,
+    resource_name VARCHAR(128) NOT NULL,
+    process_id    VARCHAR(64)  NOT NULL,
+    checksum      CHAR(64)     NOT NULL,
+    version       INT          NOT NULL,
+    process_key   BIGINT       NOT NULL,
+    deployed_at   DATETIME     NOT NULL,
+    KEY idx_workflow_deployments_process (resource_name, process_id, deployment_id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
@@ -0,0 +1,2 @@
+ALTER TABLE workflow_outbox
+    ADD COLUMN process_version INT NOT NULL DEFAULT 0 AFTER process_id;